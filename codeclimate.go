@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// codeClimateIssue is an issue of a GitLab Code Quality report, a subset of
+// the Code Climate specification, see
+// https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool.
+type codeClimateIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+// codeClimateSeverities maps the levels of SARIF rules to Code Climate
+// severities.
+var codeClimateSeverities = map[string]string{
+	"error":   "major",
+	"warning": "minor",
+}
+
+// writeCodeClimate writes the findings of report to w as a GitLab Code
+// Quality report, which merge request widgets show. The fingerprint of an
+// issue is derived from its rule and path, so that it is stable across runs.
+func writeCodeClimate(w io.Writer, report *addlicense.Report) error {
+	issues := []codeClimateIssue{}
+	for _, rule := range sarifRules {
+		for _, path := range report.Paths(rule.status) {
+			path = filepath.ToSlash(filepath.Clean(path))
+			sum := md5.Sum([]byte(rule.ID + "\x00" + path))
+			issues = append(issues, codeClimateIssue{
+				Description: rule.message,
+				CheckName:   rule.ID,
+				Fingerprint: hex.EncodeToString(sum[:]),
+				Severity:    codeClimateSeverities[rule.Config.Level],
+				Location:    codeClimateLocation{Path: path, Lines: codeClimateLines{Begin: 1}},
+			})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
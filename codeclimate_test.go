@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestWriteCodeClimate(t *testing.T) {
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a/missing.go", Status: addlicense.StatusMissing},
+		{Path: "a/ok.go", Status: addlicense.StatusOK},
+		{Path: "b/dup.py", Status: addlicense.StatusDuplicate},
+	}}
+	var out strings.Builder
+	if err := writeCodeClimate(&out, report); err != nil {
+		t.Fatal(err)
+	}
+
+	var issues []struct {
+		CheckName   string `json:"check_name"`
+		Fingerprint string
+		Severity    string
+		Location    struct {
+			Path  string
+			Lines struct{ Begin int }
+		}
+	}
+	if err := json.Unmarshal([]byte(out.String()), &issues); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2:\n%s", len(issues), out.String())
+	}
+	want := []struct{ check, severity, path string }{
+		{"missing-license-header", "major", "a/missing.go"},
+		{"duplicate-license-header", "minor", "b/dup.py"},
+	}
+	for i, w := range want {
+		is := issues[i]
+		if is.CheckName != w.check || is.Severity != w.severity || is.Location.Path != w.path || is.Location.Lines.Begin != 1 || len(is.Fingerprint) != 32 {
+			t.Errorf("issue %d is %+v, want %+v", i, is, w)
+		}
+	}
+	if issues[0].Fingerprint == issues[1].Fingerprint {
+		t.Errorf("issues have the same fingerprint %s", issues[0].Fingerprint)
+	}
+}
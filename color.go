@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// colorFlag stores the mode of the -color flag.
+type colorFlag string
+
+const (
+	colorAuto   colorFlag = ""
+	colorAlways colorFlag = "always"
+	colorNever  colorFlag = "never"
+)
+
+func (c *colorFlag) String() string {
+	if *c == colorAuto {
+		return "auto"
+	}
+	return string(*c)
+}
+
+func (c *colorFlag) Set(value string) error {
+	switch value {
+	case "auto":
+		*c = colorAuto
+	case string(colorAlways), string(colorNever):
+		*c = colorFlag(value)
+	default:
+		return fmt.Errorf("error: flag 'color' expects 'auto', '%v' or '%v'", colorAlways, colorNever)
+	}
+	return nil
+}
+
+// enabled reports whether the output written to f is colored: in auto mode,
+// if f is a terminal, unless the NO_COLOR environment variable is set or the
+// terminal is dumb.
+func (c colorFlag) enabled(f *os.File) bool {
+	switch c {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// isTerminal reports whether f is a terminal able to interpret escape
+// sequences.
+func isTerminal(f *os.File) bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ANSI escape sequences of the colors of results.
+const (
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiGreen  = "\x1b[32m"
+	ansiReset  = "\x1b[0m"
+)
+
+// statusColors maps the statuses of files to the colors of their results: red
+// for files failing, yellow for license headers to update or downgraded
+// errors, and green for files that pass.
+var statusColors = map[addlicense.Status]string{
+	addlicense.StatusMissing:       ansiRed,
+	addlicense.StatusForbidden:     ansiRed,
+	addlicense.StatusError:         ansiRed,
+	addlicense.StatusWrongLicense:  ansiYellow,
+	addlicense.StatusMissingFooter: ansiYellow,
+	addlicense.StatusDuplicate:     ansiYellow,
+	addlicense.StatusWarning:       ansiYellow,
+	addlicense.StatusOK:            ansiGreen,
+	addlicense.StatusModified:      ansiGreen,
+}
+
+// paint returns s in the color of status, if it has one.
+func paint(status addlicense.Status, s string) string {
+	if c, ok := statusColors[status]; ok {
+		return c + s + ansiReset
+	}
+	return s
+}
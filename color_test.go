@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestColorFlag(t *testing.T) {
+	f, err := ioutil.TempFile(tempDir(t), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"always", true},
+		{"never", false},
+		{"auto", false}, // not a terminal
+	}
+	for _, tt := range tests {
+		var c colorFlag
+		if err := c.Set(tt.value); err != nil {
+			t.Fatalf("Set(%q): %v", tt.value, err)
+		}
+		if c.String() != tt.value {
+			t.Errorf("Set(%q) stored %q", tt.value, c.String())
+		}
+		if got := c.enabled(f); got != tt.want {
+			t.Errorf("-color %s enabled returned %v, want %v", tt.value, got, tt.want)
+		}
+	}
+	var c colorFlag
+	if err := c.Set("sometimes"); err == nil {
+		t.Error("Set of an unknown mode returned no error")
+	}
+}
+
+func TestPaint(t *testing.T) {
+	tests := []struct {
+		status addlicense.Status
+		want   string
+	}{
+		{addlicense.StatusMissing, "\x1b[31ma.go\x1b[0m"},
+		{addlicense.StatusWrongLicense, "\x1b[33ma.go\x1b[0m"},
+		{addlicense.StatusOK, "\x1b[32ma.go\x1b[0m"},
+		{addlicense.StatusSkipped, "a.go"},
+	}
+	for _, tt := range tests {
+		if got := paint(tt.status, "a.go"); got != tt.want {
+			t.Errorf("paint(%s) returned %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestCheckColor(t *testing.T) {
+	if os.Getenv("RUNME") != "" {
+		main()
+		return
+	}
+
+	tmp := tempDir(t)
+	t.Logf("tmp dir: %s", tmp)
+	samplefile := filepath.Join(tmp, "file.c")
+	run(t, "cp", "testdata/initial/file.c", samplefile)
+
+	for _, tt := range []struct {
+		color string
+		want  string
+	}{
+		{"always", "\x1b[31m" + samplefile + "\x1b[0m\n"},
+		{"never", samplefile + "\n"},
+	} {
+		cmd := exec.Command(os.Args[0],
+			"-test.run=TestCheckColor",
+			"-l", "apache", "-c", "Google LLC", "-y", "2018",
+			"-color", tt.color, "-check", samplefile,
+		)
+		cmd.Env = []string{"RUNME=1"}
+		out, _ := cmd.Output()
+		if got := string(out); got != tt.want {
+			t.Errorf("-color %s wrote %q, want %q", tt.color, got, tt.want)
+		}
+	}
+}
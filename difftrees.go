@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+const diffTreesHelpText = `Usage: addlicense diff-trees [flags] A B
+
+Reports the differences between the license headers of the files found in
+both directory trees A and B, for example two checkouts of different branches.
+Files that exist in only one of the trees are not reported.
+
+The exit code is 1 if any difference is found.
+
+Flags:
+
+`
+
+// diffTreesMain implements the diff-trees subcommand.
+func diffTreesMain(args []string) int {
+	fs := flag.NewFlagSet("diff-trees", flag.ExitOnError)
+	var ignore stringSlice
+	fs.Var(&ignore, "ignore", "file patterns to ignore, relative to each tree, for example: -ignore vendor/**")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, diffTreesHelpText)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	n, err := addlicense.DiffTrees(os.Stdout, fs.Arg(0), fs.Arg(1), ignore)
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	if n > 0 {
+		fmt.Fprintf(os.Stdout, "%d files with differing license headers\n", n)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+const driftHelpText = `Usage: addlicense drift [flags] pattern [pattern ...]
+
+Reports the distinct license header texts found in the files of the given
+patterns, and how many files use each variant, the most used first. Header
+texts are compared without comment markers, whitespace differences and
+copyright years, so that inconsistent wording or holders stand out and can be
+unified before enforcing strict checks.
+
+Flags:
+
+`
+
+// maxVariantText is the length above which variant texts are truncated in the
+// drift report.
+const maxVariantText = 200
+
+// driftMain implements the drift subcommand.
+func driftMain(args []string) int {
+	fs := flag.NewFlagSet("drift", flag.ExitOnError)
+	var ignore stringSlice
+	fs.Var(&ignore, "ignore", "file patterns to ignore, for example: -ignore **/*.go -ignore vendor/**")
+	examples := fs.Int("examples", 3, "number of example files listed for each variant")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, driftHelpText)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	variants, missing, err := addlicense.HeaderVariants(fs.Args(), ignore)
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	writeDrift(os.Stdout, variants, len(missing), *examples)
+	return 0
+}
+
+// writeDrift writes the drift report of variants to w, listing at most
+// examples files for each.
+func writeDrift(w io.Writer, variants []addlicense.HeaderVariant, missing, examples int) {
+	total := 0
+	for _, v := range variants {
+		total += len(v.Files)
+	}
+	fmt.Fprintf(w, "%d header variants in %d files\n", len(variants), total)
+	for _, v := range variants {
+		text := v.Text
+		if len(text) > maxVariantText {
+			text = text[:maxVariantText] + "..."
+		}
+		fmt.Fprintf(w, "\n%6d files (%.1f%%)\n  %s\n", len(v.Files), 100*float64(len(v.Files))/float64(total), text)
+		if examples > 0 {
+			files := v.Files
+			if len(files) > examples {
+				files = files[:examples]
+			}
+			more := ""
+			if len(v.Files) > len(files) {
+				more = fmt.Sprintf(" and %d more", len(v.Files)-len(files))
+			}
+			fmt.Fprintf(w, "  e.g. %s%s\n", strings.Join(files, ", "), more)
+		}
+	}
+	if missing > 0 {
+		fmt.Fprintf(w, "\n%d files without license header\n", missing)
+	}
+}
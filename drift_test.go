@@ -0,0 +1,46 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestWriteDrift(t *testing.T) {
+	variants := []addlicense.HeaderVariant{
+		{Text: "Copyright YEAR Acme Licensed under MIT.", Files: []string{"a.go", "b.go", "c.go"}},
+		{Text: "Copyright YEAR Acme Inc. Licensed under MIT.", Files: []string{"d.go"}},
+	}
+	var out strings.Builder
+	writeDrift(&out, variants, 2, 2)
+	want := `2 header variants in 4 files
+
+     3 files (75.0%)
+  Copyright YEAR Acme Licensed under MIT.
+  e.g. a.go, b.go and 1 more
+
+     1 files (25.0%)
+  Copyright YEAR Acme Inc. Licensed under MIT.
+  e.g. d.go
+
+2 files without license header
+`
+	if got := out.String(); got != want {
+		t.Errorf("writeDrift wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
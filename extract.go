@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+const extractHelpText = `Usage: addlicense extract [flags] file
+
+Prints the license header of a file: the first comment block following any
+hashbang line or similar preamble, if it mentions a license. Exits with code 1
+if the file has no license header.
+
+Flags:
+
+`
+
+// errNoHeader is the error of the files without license header to extract.
+var errNoHeader = errors.New("no license header found")
+
+// extractMain implements the extract subcommand.
+func extractMain(args []string) int {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	strip := fs.Bool("strip", false, "strip the comment markers from the license header")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, extractHelpText)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	if err := writeHeader(os.Stdout, fs.Arg(0), *strip); err != nil {
+		log.Printf("%s: %v", fs.Arg(0), err)
+		if err == errNoHeader {
+			return 1
+		}
+		return 2
+	}
+	return 0
+}
+
+// writeHeader writes the license header of the file at path to w, without
+// its comment markers if strip is set.
+func writeHeader(w io.Writer, path string, strip bool) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	header, ok := addlicense.ExtractHeader(path, b, strip)
+	if !ok {
+		return errNoHeader
+	}
+	_, err = w.Write(header)
+	return err
+}
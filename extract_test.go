@@ -0,0 +1,52 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteHeader(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	licensed := filepath.Join(dir, "a.go")
+	if err := ioutil.WriteFile(licensed, []byte("// Copyright 2020 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	unlicensed := filepath.Join(dir, "b.go")
+	if err := ioutil.WriteFile(unlicensed, []byte("package b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for strip, want := range map[bool]string{
+		false: "// Copyright 2020 Acme\n// SPDX-License-Identifier: MIT\n",
+		true:  "Copyright 2020 Acme\nSPDX-License-Identifier: MIT\n",
+	} {
+		var out strings.Builder
+		if err := writeHeader(&out, licensed, strip); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != want {
+			t.Errorf("writeHeader(strip %v) wrote %q, want %q", strip, out.String(), want)
+		}
+	}
+	if err := writeHeader(ioutil.Discard, unlicensed, false); err != errNoHeader {
+		t.Errorf("writeHeader of a file without license header returned %v, want %v", err, errNoHeader)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// filterStdin adds the license header a run with opts would add to a file
+// with extension ext to the contents read from in, and writes the result to
+// out, so that editors and other tools can use addlicense as a filter. If the
+// contents can't be updated, they are written unchanged. In check only mode,
+// nothing is written and the status alone reports whether the header is
+// missing. It returns the exit code.
+func filterStdin(in io.Reader, out io.Writer, opts addlicense.Options, ext string) int {
+	b, err := ioutil.ReadAll(in)
+	if err != nil {
+		log.Printf("reading stdin: %v", err)
+		return 1
+	}
+	path := "stdin." + strings.TrimPrefix(ext, ".")
+	if opts.CheckOnly {
+		status, err := addlicense.CheckBuffer(opts, path, b)
+		if err != nil {
+			log.Printf("stdin: %v", err)
+			return 1
+		}
+		switch status {
+		case addlicense.StatusOK, addlicense.StatusSkipped, addlicense.StatusBinary, addlicense.StatusMinified:
+			return 0
+		}
+		log.Printf("stdin: license header check failed: %s", status)
+		return 1
+	}
+
+	code := 0
+	nb, status, err := addlicense.FixBuffer(opts, path, b)
+	if err != nil {
+		log.Printf("stdin: %v", err)
+		nb, code = b, 1
+	} else if status == addlicense.StatusSkipped {
+		log.Printf("stdin: unknown file type %q, left unchanged", ext)
+	}
+	if _, err := out.Write(nb); err != nil {
+		log.Printf("writing stdout: %v", err)
+		return 1
+	}
+	return code
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestFilterStdin(t *testing.T) {
+	opts := addlicense.Options{
+		Holder:  "Acme",
+		Year:    "2026",
+		License: "MIT",
+		SPDX:    addlicense.SPDXOnly,
+	}
+	check := opts
+	check.CheckOnly = true
+	tests := []struct {
+		opts addlicense.Options
+		ext  string
+		in   string
+		out  string
+		code int
+	}{
+		{opts, "go", "package a\n", "// Copyright 2026 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n", 0},
+		{opts, ".py", "#!/usr/bin/env python3\nprint()\n", "#!/usr/bin/env python3\n# Copyright 2026 Acme\n# SPDX-License-Identifier: MIT\n\nprint()\n", 0},
+		{opts, "go", "// Copyright 2020 Other\n\npackage a\n", "// Copyright 2020 Other\n\npackage a\n", 0},
+		{opts, "unknown", "text\n", "text\n", 0},
+		{check, "go", "package a\n", "", 1},
+		{check, "go", "// Copyright 2020 Other\n\npackage a\n", "", 0},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		code := filterStdin(strings.NewReader(tt.in), &out, tt.opts, tt.ext)
+		if code != tt.code || out.String() != tt.out {
+			t.Errorf("filterStdin(%q, check %v, ext %q) wrote %q and returned %d, want %q and %d", tt.in, tt.opts.CheckOnly, tt.ext, out.String(), code, tt.out, tt.code)
+		}
+	}
+}
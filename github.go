@@ -0,0 +1,149 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// writeGitHubAnnotations writes the findings of report to w as GitHub Actions
+// workflow commands, so that they are annotated inline on the pull request
+// diff. Findings are reported with the level and message of their SARIF rule.
+func writeGitHubAnnotations(w io.Writer, report *addlicense.Report) error {
+	for _, rule := range sarifRules {
+		for _, path := range report.Paths(rule.status) {
+			file := filepath.ToSlash(filepath.Clean(path))
+			_, err := fmt.Fprintf(w, "::%s file=%s,line=1,title=%s::%s\n", rule.Config.Level,
+				escapeProperty(file), escapeProperty(rule.ShortDescription.Text), escapeData(rule.message))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// escapeData escapes s for use as the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes s for use as a property value of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// outputDelimiter delimits the multiline values of step outputs.
+const outputDelimiter = "ADDLICENSE_EOF"
+
+// writeGitHubOutputs writes the counts and lists of modified and missing files
+// of report to w as GitHub Actions step outputs, in the format of the
+// GITHUB_OUTPUT file, so that later steps can react to them.
+func writeGitHubOutputs(w io.Writer, report *addlicense.Report) error {
+	for _, o := range []struct {
+		name   string
+		status addlicense.Status
+	}{
+		{"modified", addlicense.StatusModified},
+		{"missing", addlicense.StatusMissing},
+	} {
+		paths := report.Paths(o.status)
+		files := make([]string, len(paths))
+		for i, path := range paths {
+			files[i] = filepath.ToSlash(filepath.Clean(path)) + "\n"
+		}
+		_, err := fmt.Fprintf(w, "%s_count=%d\n%s_files<<%s\n%s%s\n", o.name, len(paths), o.name, outputDelimiter, strings.Join(files, ""), outputDelimiter)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendGitHubOutputs appends the step outputs of report to the GITHUB_OUTPUT
+// file, if running in GitHub Actions.
+func appendGitHubOutputs(report *addlicense.Report) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := writeGitHubOutputs(f, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// summaryStatuses lists the statuses counted in the step summary, in order.
+var summaryStatuses = []addlicense.Status{
+	addlicense.StatusOK,
+	addlicense.StatusModified,
+	addlicense.StatusMissing,
+	addlicense.StatusMissingFooter,
+	addlicense.StatusWrongLicense,
+	addlicense.StatusForbidden,
+	addlicense.StatusDuplicate,
+	addlicense.StatusSkipped,
+	addlicense.StatusBinary,
+	addlicense.StatusMinified,
+	addlicense.StatusWarning,
+	addlicense.StatusError,
+}
+
+// writeStepSummary writes report to w as the Markdown summary of a GitHub
+// Actions step: a table of the number of files per status, followed by the
+// tables of the files needing attention of writeMarkdown.
+func writeStepSummary(w io.Writer, report *addlicense.Report) error {
+	var b strings.Builder
+	b.WriteString("## addlicense\n\n| Status | Files |\n| --- | ---: |\n")
+	for _, status := range summaryStatuses {
+		if n := report.Count(status); n > 0 {
+			fmt.Fprintf(&b, "| %s | %d |\n", status, n)
+		}
+	}
+	fmt.Fprintf(&b, "| **total** | **%d** |\n\n", len(report.Results))
+	writeMarkdownFindings(&b, report)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// appendGitHubStepSummary appends the step summary of report to the
+// GITHUB_STEP_SUMMARY file, if running in GitHub Actions, so that the outcome
+// is shown on the summary page of the run.
+func appendGitHubStepSummary(report *addlicense.Report) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := writeStepSummary(f, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
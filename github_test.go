@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a/missing.go", Status: addlicense.StatusMissing},
+		{Path: "a/ok.go", Status: addlicense.StatusOK},
+		{Path: "b/dup,1.py", Status: addlicense.StatusDuplicate},
+	}}
+	var out strings.Builder
+	if err := writeGitHubAnnotations(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	want := "::error file=a/missing.go,line=1,title=Source file is missing a license header::File is missing a license header. Run addlicense to add it.\n" +
+		"::warning file=b/dup%2C1.py,line=1,title=License header is stacked twice::File has a duplicate license header. Run addlicense -fix-duplicates to remove it.\n"
+	if got := out.String(); got != want {
+		t.Errorf("writeGitHubAnnotations wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAppendGitHubOutputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addlicense")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "output")
+	if err := ioutil.WriteFile(path, []byte("other=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("GITHUB_OUTPUT", os.Getenv("GITHUB_OUTPUT"))
+	os.Setenv("GITHUB_OUTPUT", path)
+
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a/1.go", Status: addlicense.StatusMissing},
+		{Path: "a/2.go", Status: addlicense.StatusMissing},
+		{Path: "a/ok.go", Status: addlicense.StatusOK},
+	}}
+	if err := appendGitHubOutputs(report); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "other=1\n" +
+		"modified_count=0\nmodified_files<<ADDLICENSE_EOF\nADDLICENSE_EOF\n" +
+		"missing_count=2\nmissing_files<<ADDLICENSE_EOF\na/1.go\na/2.go\nADDLICENSE_EOF\n"
+	if got := string(b); got != want {
+		t.Errorf("appendGitHubOutputs wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAppendGitHubStepSummary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addlicense")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "summary.md")
+	for _, env := range []string{"GITHUB_STEP_SUMMARY", "GITHUB_SHA"} {
+		defer os.Setenv(env, os.Getenv(env))
+	}
+	os.Setenv("GITHUB_STEP_SUMMARY", path)
+	os.Setenv("GITHUB_SHA", "")
+
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a/1.go", Status: addlicense.StatusMissing},
+		{Path: "a/2.go", Status: addlicense.StatusMissing},
+		{Path: "a/ok.go", Status: addlicense.StatusOK},
+		{Path: "a/data.bin", Status: addlicense.StatusBinary},
+	}}
+	if err := appendGitHubStepSummary(report); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## addlicense\n\n" +
+		"| Status | Files |\n| --- | ---: |\n" +
+		"| ok | 1 |\n| missing | 2 |\n| binary | 1 |\n| **total** | **4** |\n\n" +
+		"2 files need attention.\n\n" +
+		"### Source file is missing a license header (2)\n"
+	if got := string(b); !strings.HasPrefix(got, want) || !strings.Contains(got, "| `a/2.go` |\n") {
+		t.Errorf("appendGitHubStepSummary wrote:\n%s\nwant it to start with:\n%s", got, want)
+	}
+}
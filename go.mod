@@ -1,8 +1,9 @@
 module github.com/google/addlicense
 
-go 1.13
+go 1.16
 
 require (
 	github.com/bmatcuk/doublestar/v4 v4.0.2
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e
+	gopkg.in/yaml.v3 v3.0.1
 )
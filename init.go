@@ -0,0 +1,171 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+const initHelpText = `Usage: addlicense init [flags] [dir]
+
+Inspects the tree at dir, the current directory by default, proposes the
+license, copyright holder and ignore patterns to use, and writes them to the
+.addlicense.yaml configuration file of dir once confirmed. Press enter to
+accept a proposed value. It then offers a dry run, listing the files that
+would get a license header.
+
+Flags:
+
+`
+
+// initMain implements the init subcommand.
+func initMain(args []string) int {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "accept all proposed values without prompting")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, initHelpText)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	if err := runInit(os.Stdin, os.Stdout, dir, *yes); err != nil {
+		log.Print(err)
+		return 1
+	}
+	return 0
+}
+
+// runInit runs the setup wizard for the tree at dir, reading answers from in
+// and writing questions to out. If yes is true, all proposed values are
+// accepted without reading in.
+func runInit(in io.Reader, out io.Writer, dir string, yes bool) error {
+	p := &prompter{in: bufio.NewScanner(in), out: out, yes: yes}
+	path := filepath.Join(dir, addlicense.DefaultConfigFile)
+	if _, err := os.Stat(path); err == nil {
+		if !p.confirm(path+" already exists, overwrite it?", false) {
+			fmt.Fprintf(out, "Keeping %s\n", path)
+			return nil
+		}
+	}
+
+	fmt.Fprintf(out, "Inspecting %s...\n", dir)
+	insp, err := addlicense.Inspect(dir)
+	if err != nil {
+		return err
+	}
+	var found []string
+	for _, ext := range insp.SortedExtensions() {
+		found = append(found, fmt.Sprintf("%s (%d)", ext, insp.Extensions[ext]))
+	}
+	if len(found) == 0 {
+		fmt.Fprintln(out, "No files of a known type found.")
+	} else {
+		fmt.Fprintf(out, "Files of a known type: %s\n", strings.Join(found, ", "))
+	}
+	if insp.LicenseFile != "" {
+		fmt.Fprintf(out, "License file: %s\n", insp.LicenseFile)
+	}
+
+	cfg := &addlicense.Config{
+		License: insp.License,
+		Holder:  insp.Holder,
+		Ignore:  insp.Ignore,
+	}
+	if cfg.License == "" {
+		cfg.License = "Apache-2.0"
+	}
+	if cfg.Holder == "" {
+		cfg.Holder = flag.Lookup("c").DefValue
+	}
+	cfg.License = p.ask("License", cfg.License)
+	cfg.Holder = p.ask("Copyright holder", cfg.Holder)
+	ignore := p.ask("Ignore patterns, comma separated", strings.Join(cfg.Ignore, ", "))
+	cfg.Ignore = splitList(ignore)
+
+	if err := addlicense.WriteConfig(path, cfg); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote %s\n", path)
+
+	if !p.confirm("Run a dry run now?", true) {
+		return nil
+	}
+	opts := cfg.Options(dir)
+	opts.CheckOnly = true
+	opts.Logger = log.New(out, "", 0)
+	report, err := addlicense.Run(context.Background(), opts)
+	if report == nil {
+		return err
+	}
+	missing := report.Paths(addlicense.StatusMissing)
+	for _, path := range missing {
+		fmt.Fprintln(out, path)
+	}
+	fmt.Fprintf(out, "%d files would get a license header.\n", len(missing))
+	return nil
+}
+
+// prompter asks questions on the command line.
+type prompter struct {
+	in  *bufio.Scanner
+	out io.Writer
+	yes bool // accept all proposed values
+}
+
+// ask asks question and returns the answer, or def if the answer is empty.
+func (p *prompter) ask(question, def string) string {
+	fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	if p.yes || !p.in.Scan() {
+		fmt.Fprintln(p.out)
+		return def
+	}
+	if v := strings.TrimSpace(p.in.Text()); v != "" {
+		return v
+	}
+	return def
+}
+
+// confirm asks a yes or no question and returns the answer, or def if the
+// answer is empty.
+func (p *prompter) confirm(question string, def bool) bool {
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+	switch strings.ToLower(p.ask(question, choices)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return def
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestRunInit(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "LICENSE"), []byte("Apache License\nVersion 2.0, January 2004\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// keep the proposed license, set the holder and no ignore pattern, then
+	// accept the dry run, which lists main.go but not the hidden configuration
+	// file
+	var out strings.Builder
+	if err := runInit(strings.NewReader("\nAcme Corp\n\n\n"), &out, dir, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Files of a known type: .go (1)", "License [Apache-2.0]: ", "main.go\n", "1 files would get a license header."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output doesn't contain %q:\n%s", want, out.String())
+		}
+	}
+	c, err := addlicense.ReadConfig(filepath.Join(dir, addlicense.DefaultConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.License != "Apache-2.0" || c.Holder != "Acme Corp" || len(c.Ignore) != 0 {
+		t.Errorf("wrote config %+v", c)
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "main.go")); string(b) != "package main\n" {
+		t.Errorf("dry run modified main.go: %q", b)
+	}
+
+	// an existing configuration is kept unless confirmed
+	out.Reset()
+	if err := runInit(strings.NewReader(""), &out, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Keeping") {
+		t.Errorf("existing configuration not kept:\n%s", out.String())
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// jsonReport is the JSON report of a check: the outcome of each file, and
+// aggregates of them for compliance dashboards.
+type jsonReport struct {
+	Files   []jsonFile       `json:"files"`
+	Total   int              `json:"total"`
+	Summary addlicense.Stats `json:"summary"`
+}
+
+type jsonFile struct {
+	Path    string            `json:"path"`
+	Status  addlicense.Status `json:"status"`
+	License string            `json:"license,omitempty"`
+	Holder  string            `json:"holder,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// writeJSON writes the results of report to w as a JSON report.
+func writeJSON(w io.Writer, report *addlicense.Report) error {
+	res := jsonReport{
+		Files:   make([]jsonFile, 0, len(report.Results)),
+		Total:   len(report.Results),
+		Summary: report.Stats(),
+	}
+	for _, r := range report.Results {
+		f := jsonFile{
+			Path:    filepath.ToSlash(filepath.Clean(r.Path)),
+			Status:  r.Status,
+			License: r.License,
+			Holder:  r.Holder,
+			Error:   resultError(r),
+		}
+		res.Files = append(res.Files, f)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+// resultError returns the error message of res, if any. The statuses of
+// missing headers and footers tell their errors, which are left out.
+func resultError(res addlicense.Result) string {
+	if res.Err != nil && (res.Status == addlicense.StatusError || res.Status == addlicense.StatusWarning) {
+		return res.Err.Error()
+	}
+	return ""
+}
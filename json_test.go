@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestWriteJSON(t *testing.T) {
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a/ok.go", Status: addlicense.StatusOK, License: "Apache-2.0", Holder: "Google LLC"},
+		{Path: "a/missing.go", Status: addlicense.StatusMissing, Err: addlicense.ErrMissingLicense},
+		{Path: "b/ok.py", Status: addlicense.StatusOK, License: "MIT", Holder: "Google LLC"},
+		{Path: "b/broken.py", Status: addlicense.StatusError, Err: errors.New("permission denied")},
+	}}
+	var out strings.Builder
+	if err := writeJSON(&out, report); err != nil {
+		t.Fatal(err)
+	}
+
+	var res struct {
+		Files []struct {
+			Path, Status, License, Holder, Error string
+		}
+		Total   int
+		Summary struct {
+			Extensions map[string]map[string]int
+			Licenses   map[string]int
+			Holders    map[string]int
+		}
+	}
+	if err := json.Unmarshal([]byte(out.String()), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 4 || len(res.Files) != 4 {
+		t.Fatalf("unexpected JSON report:\n%s", out.String())
+	}
+	if f := res.Files[0]; f.Path != "a/ok.go" || f.Status != "ok" || f.License != "Apache-2.0" || f.Holder != "Google LLC" {
+		t.Errorf("first file is %+v", f)
+	}
+	if res.Files[1].Error != "" || res.Files[3].Error != "permission denied" {
+		t.Errorf("files have errors %q and %q, want none and %q", res.Files[1].Error, res.Files[3].Error, "permission denied")
+	}
+	wantExts := map[string]map[string]int{
+		"go": {"ok": 1, "missing": 1},
+		"py": {"ok": 1, "error": 1},
+	}
+	if !reflect.DeepEqual(res.Summary.Extensions, wantExts) {
+		t.Errorf("extensions summary is %v, want %v", res.Summary.Extensions, wantExts)
+	}
+	if want := map[string]int{"Apache-2.0": 1, "MIT": 1}; !reflect.DeepEqual(res.Summary.Licenses, want) {
+		t.Errorf("licenses summary is %v, want %v", res.Summary.Licenses, want)
+	}
+	if want := map[string]int{"Google LLC": 2}; !reflect.DeepEqual(res.Summary.Holders, want) {
+		t.Errorf("holders summary is %v, want %v", res.Summary.Holders, want)
+	}
+}
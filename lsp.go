@@ -0,0 +1,183 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// lspLiteVersion is the version of the --lsp-lite protocol, incremented on
+// incompatible changes.
+const lspLiteVersion = 1
+
+// lspRequest is a request of the --lsp-lite protocol, read from one line of
+// stdin.
+type lspRequest struct {
+	// ID is echoed in the response, so that clients can match them.
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	// Path is the path of the file edited in the buffer, which selects its
+	// comment style, license and ignore patterns.
+	Path string `json:"path"`
+	// Text is the contents of the buffer.
+	Text string `json:"text"`
+}
+
+// lspResponse is a response of the --lsp-lite protocol, written to one line of
+// stdout.
+type lspResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// lspPosition is a zero-based position in a buffer, like in the Language
+// Server Protocol.
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+// lspDiagnostic reports a license issue of a buffer.
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity string   `json:"severity"`
+	// Code is the one of the SARIF rule of the issue.
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspInitializeResult struct {
+	Version int      `json:"version"`
+	Methods []string `json:"methods"`
+}
+
+type lspCheckResult struct {
+	Status      addlicense.Status `json:"status"`
+	Diagnostics []lspDiagnostic   `json:"diagnostics"`
+}
+
+type lspFixResult struct {
+	Status  addlicense.Status `json:"status"`
+	Changed bool              `json:"changed"`
+	Text    string            `json:"text"`
+}
+
+type lspRenderResult struct {
+	Header string `json:"header"`
+}
+
+// serveLSPLite serves the --lsp-lite protocol for editor extensions: one JSON
+// request per line of in, answered by one JSON response per line of out,
+// checking, fixing or rendering headers with the options of the command line.
+// It returns the exit code once in is closed.
+func serveLSPLite(in io.Reader, out io.Writer, opts addlicense.Options) int {
+	sc := bufio.NewScanner(in)
+	sc.Buffer(nil, 64<<20) // buffers hold whole files
+	enc := json.NewEncoder(out)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req lspRequest
+		var resp lspResponse
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp.ID = req.ID
+			resp.Result, err = handleLSPLite(req, opts)
+			if err != nil {
+				resp.Error = err.Error()
+			}
+		}
+		if err := enc.Encode(resp); err != nil {
+			log.Print(err)
+			return 1
+		}
+	}
+	if err := sc.Err(); err != nil {
+		log.Print(err)
+		return 1
+	}
+	return 0
+}
+
+// handleLSPLite returns the result of req.
+func handleLSPLite(req lspRequest, opts addlicense.Options) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		return lspInitializeResult{lspLiteVersion, []string{"initialize", "check", "fix", "render"}}, nil
+	case "check":
+		status, err := addlicense.CheckBuffer(opts, req.Path, []byte(req.Text))
+		if err != nil {
+			return nil, err
+		}
+		return lspCheckResult{status, lspDiagnostics(status, req.Text)}, nil
+	case "fix":
+		b, status, err := addlicense.FixBuffer(opts, req.Path, []byte(req.Text))
+		if err != nil {
+			return nil, err
+		}
+		return lspFixResult{status, string(b) != req.Text, string(b)}, nil
+	case "render":
+		header, err := addlicense.RenderHeader(opts, req.Path)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, fmt.Errorf("%s: unknown file type", req.Path)
+		}
+		return lspRenderResult{string(header)}, nil
+	}
+	return nil, fmt.Errorf("unknown method %q", req.Method)
+}
+
+// lspDiagnostics returns the diagnostics of a buffer holding text checked with
+// status. Header issues are reported on the first line, and missing footers
+// on the last one.
+func lspDiagnostics(status addlicense.Status, text string) []lspDiagnostic {
+	var code, message string
+	line := 0
+	switch status {
+	case addlicense.StatusMissing:
+		code, message = "missing-license-header", "missing license header"
+	case addlicense.StatusWrongLicense:
+		code, message = "wrong-license-header", "license header isn't the one of the assigned license"
+	case addlicense.StatusForbidden:
+		code, message = "forbidden-license-header", "license header of a forbidden license"
+	case addlicense.StatusDuplicate:
+		code, message = "duplicate-license-header", "duplicate license header"
+	case addlicense.StatusMissingFooter:
+		code, message = "missing-license-footer", "missing license footer"
+		line = bytes.Count([]byte(text), []byte("\n"))
+	default:
+		return []lspDiagnostic{}
+	}
+	pos := lspPosition{Line: line}
+	return []lspDiagnostic{{Range: lspRange{pos, pos}, Severity: "warning", Code: code, Message: message}}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestServeLSPLite(t *testing.T) {
+	opts := addlicense.Options{Holder: "Acme", Year: "2020", License: "MIT"}
+	in := strings.Join([]string{
+		`{"id":1,"method":"initialize"}`,
+		`{"id":2,"method":"check","path":"a.go","text":"package a\n"}`,
+		`{"id":3,"method":"fix","path":"a.go","text":"package a\n"}`,
+		`{"id":"r","method":"render","path":"a.sh"}`,
+		`{"id":5,"method":"render","path":"a.unknown"}`,
+		`{"id":6,"method":"nope"}`,
+		`not json`,
+	}, "\n")
+	var out strings.Builder
+	if code := serveLSPLite(strings.NewReader(in), &out, opts); code != 0 {
+		t.Fatalf("serveLSPLite exited with %d", code)
+	}
+	want := []string{
+		`{"id":1,"result":{"version":1,"methods":["initialize","check","fix","render"]}}`,
+		`{"id":2,"result":{"status":"missing","diagnostics":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"severity":"warning","code":"missing-license-header","message":"missing license header"}]}}`,
+		`{"id":3,"result":{"status":"modified","changed":true,"text":"// Copyright (c) 2020 Acme\n//\n`,
+		`{"id":"r","result":{"header":"# Copyright (c) 2020 Acme\n`,
+		`{"id":5,"error":"a.unknown: unknown file type"}`,
+		`{"id":6,"error":"unknown method \"nope\""}`,
+		`{"id":null,"error":"invalid request: `,
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d responses, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, want[i]) {
+			t.Errorf("response %d:\ngot  %s\nwant %s...", i, line, want[i])
+		}
+	}
+}
+
+func TestLSPDiagnostics(t *testing.T) {
+	if d := lspDiagnostics(addlicense.StatusOK, "a\n"); len(d) != 0 {
+		t.Errorf("lspDiagnostics of a valid buffer returned %v", d)
+	}
+	d := lspDiagnostics(addlicense.StatusMissingFooter, "a\nb\nc\n")
+	if len(d) != 1 || d[0].Range.Start.Line != 3 || d[0].Code != "missing-license-footer" {
+		t.Errorf("lspDiagnostics of a buffer missing its footer returned %+v", d)
+	}
+}
@@ -17,21 +17,21 @@
 package main
 
 import (
-	"bytes"
+	"bufio"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
-	"path/filepath"
-	"regexp"
+	"strconv"
 	"strings"
-	"text/template"
 	"time"
 
 	doublestar "github.com/bmatcuk/doublestar/v4"
-	"golang.org/x/sync/errgroup"
+	"github.com/google/addlicense/pkg/addlicense"
 )
 
 const helpText = `Usage: addlicense [flags] pattern [pattern ...]
@@ -43,7 +43,17 @@ It modifies all source files in place and avoids adding a license header
 to any file that already has one.
 
 The pattern argument can be provided multiple times, and may also refer
-to single files.
+to single files, or be a doublestar glob such as 'src/**/*.go'.
+
+Commands:
+
+  diff-trees A B   report license header differences between two trees
+  drift PATTERN    report the variants of license header texts in use
+  extract FILE     print the license header of a file
+  init [DIR]       create the .addlicense.yaml configuration of a tree
+  rollback SNAP    restore the files modified by a run started with -snapshot
+  test-template    compare a license template rendered in every comment style
+                   with golden files
 
 Flags:
 
@@ -52,14 +62,75 @@ Flags:
 var (
 	skipExtensionFlags stringSlice
 	ignorePatterns     stringSlice
+	includePatterns    stringSlice
+	hiddenPatterns     stringSlice
+	hiddenPolicy       hiddenFlag
+	markerFlags        stringSlice
+	presetFlags        stringSlice
 	spdx               spdxFlag
-
-	holder    = flag.String("c", "Google LLC", "copyright holder")
-	license   = flag.String("l", "apache", "license type: apache, bsd, mit, mpl")
-	licensef  = flag.String("f", "", "license file")
-	year      = flag.String("y", fmt.Sprint(time.Now().Year()), "copyright year(s)")
-	verbose   = flag.Bool("v", false, "verbose mode: print the name of the files that are modified or were skipped")
-	checkonly = flag.Bool("check", false, "check only mode: verify presence of license headers and exit with non-zero code if missing")
+	yearNormalization  yearPolicyFlag
+	warnPolicy         warnRules
+	dryRun             bool
+	noDefaultIgnores   bool
+	walkVCS            bool
+	followSymlinks     bool
+	minified           bool
+	backup             bool
+	quiet              bool
+	progress           bool
+	logLevel           logLevelFlag
+	colorMode          colorFlag
+	maxSize            sizeFlag
+	licenseConfigured  bool // set if the configuration file sets the license
+	footerFlags        stringSlice
+	contributorFlags   stringSlice
+	forbidFlags        stringSlice
+	subtreeLicenses    map[string]string // licenses of subtrees, from the configuration file
+	insertAfter        map[string]string // anchors of license headers, from the configuration file
+	extStyles          = make(map[string]string)
+	commentStyles      = make(map[string]string)
+
+	holder      = flag.String("c", "Google LLC", "copyright holder, or 'auto' to derive it from the git remote, go.mod or git config")
+	license     = flag.String("l", "apache", "license type: apache, bsd, mit, mpl, unlicense, cc0")
+	licensef    = flag.String("f", "", "license file")
+	licenseRef  = flag.String("f-from-file", "", "file with a license header to use as the license template, for example: -f-from-file cmd/main.go")
+	year        = flag.String("y", fmt.Sprint(time.Now().Year()), "copyright year(s), or 'git' to use the years of the first and last commits of each file")
+	lspLite     = flag.Bool("lsp-lite", false, "serve the editor integration protocol on stdin and stdout instead of processing files")
+	maxDepth    = flag.Int("maxdepth", 0, "maximum depth of the files processed below each pattern, 1 for the files directly in it, 0 for no limit")
+	notice      = flag.String("notice", "", "additional notice, such as an export control statement or a trademark notice, following the license text in license headers")
+	noYear      = flag.Bool("no-year", false, "omit the copyright year from license headers, same as -y \"\"")
+	verbose     = flag.Bool("v", false, "verbose mode: print the name of the files that are modified or were skipped")
+	checkonly   = flag.Bool("check", false, "check only mode: verify presence of license headers and exit with non-zero code if missing")
+	onlyExt     = flag.String("only-ext", "", "comma separated list of file extensions to restrict processing to, for example: -only-ext go,py,ts")
+	replace     = flag.String("replace", "", "license type whose existing license headers are replaced with headers of the -l license, preserving their holder and years, for example: -replace mit")
+	fixDups     = flag.Bool("fix-duplicates", false, "remove the redundant copy of license headers stacked twice instead of adding missing ones")
+	remove      = flag.Bool("remove", false, "strip existing license headers instead of adding missing ones")
+	holdersf    = flag.String("rewrite-holders", "", "CSV file of pattern,holder[,year] records: rewrite the holder and years of existing license headers instead of adding missing ones")
+	outputf     = flag.String("output", "", "with -check, write the list of files missing license headers to this file and print a summary grouped by directory instead")
+	format      = flag.String("format", "text", "with -check, format of the results: text, sarif, rdjson, codeclimate, markdown or json; or ndjson, with or without -check, to write an event per file to stdout as soon as it is processed")
+	chunk       = flag.Int("chunk", 0, "with -check, split the list of files missing license headers into pages of at most this many files")
+	otelURL     = flag.String("otel-endpoint", "", "base URL of an OpenTelemetry collector to export traces and metrics of the run to with OTLP/HTTP, for example: http://localhost:4318")
+	gitStaged   = flag.Bool("git-staged", false, "only process files staged in the git index, restricted to the given patterns if any")
+	configf     = flag.String("config", addlicense.DefaultConfigFile, "configuration file providing default flag values, ignored if missing unless set explicitly")
+	keepShort   = flag.Bool("keep-short", false, "with -replace, keep existing short headers, a copyright statement followed by an SPDX identifier, short instead of replacing them with the license text")
+	fileType    = flag.String("spdx-file-type", "", "with -s=tags, value of the SPDX-FileType tag, for example: SOURCE")
+	annotate    = flag.Bool("github-annotations", os.Getenv("GITHUB_ACTIONS") == "true", "with -check, print GitHub Actions annotations for the files missing license headers (default true when running in GitHub Actions)")
+	tolerate    = flag.Int("tolerate-errors", 0, "number of files that may fail with errors, such as on a flaky network mount, without failing the run; tolerated errors exit with code 3")
+	fileTimeout = flag.Duration("file-timeout", 0, "maximum time spent processing a file before failing it, for example: -file-timeout 30s (default no limit)")
+	filesf      = flag.String("files", "", "file listing the files to process, one per line, in addition to the patterns, or - to read the list from stdin")
+	filesFrom   = flag.String("files-from", "", "file listing the files to process separated by NUL bytes, as printed by git ls-files -z, in addition to the patterns, or - to read the list from stdin")
+	gitSince    = flag.String("since", "", "only process files added or modified since the merge base of a git ref and HEAD, restricted to the given patterns if any, for example: -since origin/main")
+	gitTracked  = flag.Bool("git-tracked", false, "only process files tracked by git, restricted to the given patterns if any")
+	cachef      = flag.String("cache", "", "file recording the outcome of processing each file with a hash of its contents, so that later runs with the same flags skip the unchanged files")
+	stdinExt    = flag.String("ext", "", "with - as the only pattern, extension of the file read from stdin, whose licensed contents are written to stdout, for example: -ext go -")
+	outDir      = flag.String("o", "", "directory where the processed files are written, with license headers added, mirroring the input tree and leaving the sources untouched")
+	backupDir   = flag.String("backup-dir", "", "directory where the original contents of modified files are saved, at their relative paths, instead of next to them with -backup")
+	snapshot    = flag.String("snapshot", "", "directory where the original contents of modified files are saved, so that \"addlicense rollback\" can restore them")
+	workers     = flag.Int("workers", addlicense.DefaultWorkers, "number of files processed concurrently")
+	profile     = flag.Bool("profile", false, "print the duration of the stages of the run and statistics of the queue of files waiting for a worker to stderr")
+	walkWorkers = flag.Int("walk-workers", 1, "number of directory subtrees walked concurrently, which speeds up the walk of large trees on network file systems")
+	verifyGo    = flag.Bool("verify-compiles", false, "verify that updated Go files still parse, keep their build constraints and stay gofmt formatted, failing them otherwise")
+	gitAdded    = flag.Bool("git-added-only", false, "with -git-staged, only process newly added files and leave modified ones alone")
 )
 
 func init() {
@@ -67,9 +138,33 @@ func init() {
 		fmt.Fprint(os.Stderr, helpText)
 		flag.PrintDefaults()
 	}
+	flag.BoolVar(&dryRun, "n", false, "dry run: write nothing, print a unified diff of the changes that would be made instead")
+	flag.BoolVar(&dryRun, "dry-run", false, "same as -n")
+	flag.BoolVar(&backup, "backup", false, "save the original contents of modified files next to them, as file.ext"+addlicense.BackupSuffix)
+	flag.BoolVar(&followSymlinks, "follow-symlinks", false, "walk the directories that symbolic links point to, once each")
+	flag.BoolVar(&progress, "progress", false, "print the number of files processed and found to stderr as the run goes, on a status line on terminals and every 10s otherwise")
+	flag.BoolVar(&quiet, "q", false, "quiet mode: only log errors, warnings and the findings of -check, same as -log-level warn")
+	flag.BoolVar(&minified, "minified", false, "also process minified JavaScript and CSS files, which are skipped by default")
+	flag.BoolVar(&walkVCS, "walk-vcs", false, "also walk the version control metadata directories: "+strings.Join(addlicense.VCSDirs, ", "))
+	flag.BoolVar(&noDefaultIgnores, "no-default-ignores", false, "also walk the directories skipped by default: "+strings.Join(addlicense.DefaultIgnoredDirs, ", "))
+	flag.Var(extStyleFlag(extStyles), "ext-style", "comment style of files with an extension, for example: -ext-style elm=dash (one of: "+strings.Join(addlicense.CommentStyleNames(), ", ")+")")
+	flag.Var(extStyleFlag(commentStyles), "comment-style", "custom comment style of files with an extension, as a line comment prefix or the comma-separated start, line prefix and end of block comments, for example: -comment-style \".foo=# \" -comment-style \".bar=/*, * , */\"")
+	flag.Var(&footerFlags, "footer", "license footer template file required at the end of files, optionally restricted to an extension, for example: -footer c=footer.tpl")
 	flag.Var(&skipExtensionFlags, "skip", "[deprecated: see -ignore] file extensions to skip, for example: -skip rb -skip go")
 	flag.Var(&ignorePatterns, "ignore", "file patterns to ignore, for example: -ignore **/*.go -ignore vendor/**")
-	flag.Var(&spdx, "s", "Include SPDX identifier in license header. Set -s=only to only include SPDX identifier.")
+	flag.Var(&includePatterns, "include", "file patterns to restrict processing to, for example: -include **/*.go -include **/*.proto")
+	flag.Var(&maxSize, "max-size", "size above which files are skipped, for example: -max-size 5MB (default no limit)")
+	flag.Var(&colorMode, "color", "color the results of -check and the lines logged about files: 'auto' on terminals, 'always' or 'never' (default auto)")
+	flag.Var(&logLevel, "log-level", "lines logged besides errors: 'debug' adds the files modified or skipped, as -v does, 'info' notes about the run, 'warn' only warnings and the findings of -check (default info)")
+	flag.Var(&hiddenPolicy, "hidden", "handling of hidden files and directories: 'skip' or 'include' (default skip)")
+	flag.Var(&hiddenPatterns, "include-hidden", "hidden file patterns to process even if hidden files are skipped, for example: -include-hidden .github/**")
+	flag.Var(&markerFlags, "marker", "additional phrase identifying an existing license header, for example: -marker \"all rights reserved\"")
+	flag.Var(&presetFlags, "preset", "bundled file patterns to apply, for example: -preset github-actions (one of: "+strings.Join(addlicense.PresetNames(), ", ")+")")
+	flag.Var(&warnPolicy, "warn", "downgrade errors of a class (permission, not-exist, timeout, io) to warnings, optionally for files matching a pattern, for example: -warn permission=vendor/**")
+	flag.Var(&yearNormalization, "normalize-years", "rewrite the years of existing license headers: 'ranges' collapses consecutive years into ranges, 'first-current' uses the first year up to the current one")
+	flag.Var(&forbidFlags, "forbid", "with -check, license type whose license headers fail the check, including its variants, for example: -forbid GPL-3.0")
+	flag.Var(&contributorFlags, "spdx-contributor", "with -s=tags, value of an SPDX-FileContributor tag, for example: -spdx-contributor \"Jane Doe <jane@example.com>\"")
+	flag.Var(&spdx, "s", "Include SPDX identifier in license header. Set -s=only to only include SPDX identifier, -s=tags to use SPDX file tags, or -s=short for the short template.")
 }
 
 // stringSlice stores the results of a repeated command line flag as a string slice.
@@ -84,14 +179,22 @@ func (i *stringSlice) Set(value string) error {
 	return nil
 }
 
-// spdxFlag defines the line flag behavior for specifying SPDX support.
-type spdxFlag string
+// extStyleFlag stores the ext=style values of the -ext-style flag.
+type extStyleFlag map[string]string
 
-const (
-	spdxOff  spdxFlag = ""
-	spdxOn   spdxFlag = "true" // value set by flag package on bool flag
-	spdxOnly spdxFlag = "only"
-)
+func (f extStyleFlag) String() string { return fmt.Sprint(map[string]string(f)) }
+
+func (f extStyleFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("expected ext=style, got %q", value)
+	}
+	f[value[:i]] = value[i+1:]
+	return nil
+}
+
+// spdxFlag defines the line flag behavior for specifying SPDX support.
+type spdxFlag addlicense.SPDXMode
 
 // IsBoolFlag causes a bare '-s' flag to be set as the string 'true'.  This
 // allows the use of the bare '-s' or setting a string '-s=only'.
@@ -99,282 +202,606 @@ func (i *spdxFlag) IsBoolFlag() bool { return true }
 func (i *spdxFlag) String() string   { return string(*i) }
 
 func (i *spdxFlag) Set(value string) error {
-	v := spdxFlag(value)
-	if v != spdxOn && v != spdxOnly {
-		return fmt.Errorf("error: flag 's' expects '%v' or '%v'", spdxOn, spdxOnly)
+	v := addlicense.SPDXMode(value)
+	switch v {
+	case addlicense.SPDXOn, addlicense.SPDXOnly, addlicense.SPDXTags, addlicense.SPDXShort:
+	default:
+		return fmt.Errorf("error: flag 's' expects '%v', '%v', '%v' or '%v'", addlicense.SPDXOn, addlicense.SPDXOnly, addlicense.SPDXTags, addlicense.SPDXShort)
 	}
-	*i = v
+	*i = spdxFlag(v)
 	return nil
 }
 
-func main() {
-	flag.Parse()
-	if flag.NArg() == 0 {
-		flag.Usage()
-		os.Exit(1)
+// yearPolicyFlag stores the policy of the -normalize-years flag.
+type yearPolicyFlag addlicense.YearPolicy
+
+func (p *yearPolicyFlag) String() string { return string(*p) }
+
+func (p *yearPolicyFlag) Set(value string) error {
+	v := addlicense.YearPolicy(value)
+	if v != addlicense.YearsRanges && v != addlicense.YearsFirstCurrent {
+		return fmt.Errorf("error: flag 'normalize-years' expects '%v' or '%v'", addlicense.YearsRanges, addlicense.YearsFirstCurrent)
 	}
+	*p = yearPolicyFlag(v)
+	return nil
+}
 
-	// convert -skip flags to -ignore equivalents
-	for _, s := range skipExtensionFlags {
-		ignorePatterns = append(ignorePatterns, fmt.Sprintf("**/*.%s", s))
+// logLevelFlag stores the level of the -log-level flag.
+type logLevelFlag addlicense.LogLevel
+
+func (l *logLevelFlag) String() string { return addlicense.LogLevel(*l).String() }
+
+func (l *logLevelFlag) Set(value string) error {
+	v, err := addlicense.ParseLogLevel(value)
+	if err != nil {
+		return err
 	}
-	// verify that all ignorePatterns are valid
-	for _, p := range ignorePatterns {
-		if !doublestar.ValidatePattern(p) {
-			log.Fatalf("-ignore pattern %q is not valid", p)
-		}
+	*l = logLevelFlag(v)
+	return nil
+}
+
+// logInfo logs a note about the run, unless the log level is warn.
+func logInfo(format string, v ...interface{}) {
+	if addlicense.LogLevel(logLevel).Enabled(addlicense.LogInfo) {
+		log.Printf(format, v...)
+	}
+}
+
+// hiddenFlag stores the policy of the -hidden flag.
+type hiddenFlag addlicense.HiddenPolicy
+
+func (h *hiddenFlag) String() string {
+	if *h == hiddenFlag(addlicense.HiddenSkip) {
+		return "skip"
 	}
+	return string(*h)
+}
 
-	// map legacy license values
-	if t, ok := legacyLicenseTypes[*license]; ok {
-		*license = t
+func (h *hiddenFlag) Set(value string) error {
+	switch value {
+	case "skip":
+		*h = hiddenFlag(addlicense.HiddenSkip)
+	case string(addlicense.HiddenInclude):
+		*h = hiddenFlag(addlicense.HiddenInclude)
+	default:
+		return fmt.Errorf("error: flag 'hidden' expects 'skip' or '%v'", addlicense.HiddenInclude)
 	}
+	return nil
+}
 
-	data := licenseData{
-		Year:   *year,
-		Holder: *holder,
-		SPDXID: *license,
+// sizeFlag stores the size in bytes of the -max-size flag.
+type sizeFlag int64
+
+// sizeUnits maps the suffixes of sizes to their multipliers, longest first so
+// that "MB" isn't parsed as a number of bytes ending with "M".
+var sizeUnits = []struct {
+	suffix string
+	n      int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+	{"KB", 1000}, {"MB", 1000 * 1000}, {"GB", 1000 * 1000 * 1000},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+	{"B", 1},
+}
+
+func (s *sizeFlag) String() string {
+	if *s == 0 {
+		return ""
 	}
+	return strconv.FormatInt(int64(*s), 10)
+}
 
-	tpl, err := fetchTemplate(*license, *licensef, spdx)
-	if err != nil {
-		log.Fatal(err)
+func (s *sizeFlag) Set(value string) error {
+	v, n := strings.TrimSpace(value), int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(strings.ToUpper(v), strings.ToUpper(u.suffix)) {
+			v, n = strings.TrimSpace(v[:len(v)-len(u.suffix)]), u.n
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return fmt.Errorf("error: flag 'max-size' expects a size such as 5MB or 512KiB, got %q", value)
 	}
-	t, err := template.New("").Parse(tpl)
+	*s = sizeFlag(f * float64(n))
+	return nil
+}
+
+// warnRules stores the results of the repeated -warn flag.
+type warnRules []addlicense.WarnRule
+
+func (r *warnRules) String() string {
+	return fmt.Sprint(*r)
+}
+
+func (r *warnRules) Set(value string) error {
+	rule, err := addlicense.ParseWarnRule(value)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("error: flag 'warn': %v", err)
 	}
+	*r = append(*r, rule)
+	return nil
+}
 
-	// process at most 1000 files in parallel
-	ch := make(chan *file, 1000)
-	done := make(chan struct{})
-	go func() {
-		var wg errgroup.Group
-		for f := range ch {
-			f := f // https://golang.org/doc/faq#closures_and_goroutines
-			wg.Go(func() error {
-				if *checkonly {
-					// Check if file extension is known
-					lic, err := licenseHeader(f.path, t, data)
-					if err != nil {
-						log.Printf("%s: %v", f.path, err)
-						return err
-					}
-					if lic == nil { // Unknown fileExtension
-						return nil
-					}
-					// Check if file has a license
-					hasLicense, err := fileHasLicense(f.path)
-					if err != nil {
-						log.Printf("%s: %v", f.path, err)
-						return err
-					}
-					if !hasLicense {
-						fmt.Printf("%s\n", f.path)
-						return errors.New("missing license header")
-					}
-				} else {
-					modified, err := addLicense(f.path, f.mode, t, data)
-					if err != nil {
-						log.Printf("%s: %v", f.path, err)
-						return err
-					}
-					if *verbose && modified {
-						log.Printf("%s modified", f.path)
-					}
-				}
-				return nil
-			})
+// loadConfig applies the settings of the configuration file to the flags not
+// set on the command line. Ignore and include patterns, presets and markers
+// are added to those of the command line.
+func loadConfig() error {
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	c, err := addlicense.ReadConfig(*configf)
+	if os.IsNotExist(err) && !set["config"] {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if c.License != "" && !set["l"] {
+		*license = c.License
+	}
+	licenseConfigured = c.License != ""
+	if c.Holder != "" && !set["c"] {
+		*holder = c.Holder
+	}
+	if c.Year != "" && !set["y"] {
+		*year = c.Year
+	}
+	if c.Notice != "" && !set["notice"] {
+		*notice = c.Notice
+	}
+	if c.SPDX != addlicense.SPDXOff && !set["s"] {
+		spdx = spdxFlag(c.SPDX)
+	}
+	if c.KeepShort && !set["keep-short"] {
+		*keepShort = true
+	}
+	ignorePatterns = append(ignorePatterns, c.Ignore...)
+	includePatterns = append(includePatterns, c.Include...)
+	presetFlags = append(presetFlags, c.Presets...)
+	markerFlags = append(markerFlags, c.Markers...)
+	subtreeLicenses = c.Licenses
+	insertAfter = c.InsertAfter
+	for ext, style := range c.ExtStyles {
+		if _, ok := extStyles[ext]; !ok {
+			extStyles[ext] = style
 		}
-		err := wg.Wait()
-		close(done)
-		if err != nil {
-			os.Exit(1)
+	}
+	for ext, style := range c.CommentStyles {
+		if _, ok := commentStyles[ext]; !ok {
+			commentStyles[ext] = style
 		}
-	}()
+	}
+	for ext, path := range c.Footers {
+		footerFlags = append(footerFlags, ext+"="+path)
+	}
+	return nil
+}
 
-	for _, d := range flag.Args() {
-		if err := walk(ch, d); err != nil {
-			log.Fatal(err)
-		}
+// detectLicense sets the license type to the one of the license file of the
+// current directory, unless the -l or -f flags or the configuration file set
+// the license.
+func detectLicense() {
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if set["l"] || set["f"] || licenseConfigured {
+		return
+	}
+	path, lic := addlicense.DetectLicenseFile(".")
+	if lic == "" {
+		return
 	}
-	close(ch)
-	<-done
+	logInfo("using the %s license detected in %s, set -l to override", lic, path)
+	*license = lic
 }
 
-type file struct {
-	path string
-	mode os.FileMode
+// loadIgnoreFile adds the patterns of the ignore file of the current
+// directory, if any, to those of the -ignore flags.
+func loadIgnoreFile() error {
+	patterns, err := addlicense.ReadIgnoreFile(addlicense.DefaultIgnoreFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ignorePatterns = append(ignorePatterns, patterns...)
+	return nil
 }
 
-func walk(ch chan<- *file, start string) error {
-	return filepath.Walk(start, func(path string, fi os.FileInfo, err error) error {
-		if err != nil {
-			log.Printf("%s error: %v", path, err)
-			return nil
+// readFooters reads the footer templates of the -footer flags, of the form
+// [ext=]file, and returns them by extension, or "*" for all extensions.
+func readFooters(flags []string) (map[string]string, error) {
+	footers := make(map[string]string)
+	for _, f := range flags {
+		ext, path := "*", f
+		if i := strings.Index(f, "="); i >= 0 {
+			ext, path = f[:i], f[i+1:]
 		}
-		if fi.IsDir() {
-			return nil
-		}
-		if fileMatches(path, ignorePatterns) {
-			if *verbose {
-				log.Printf("skipping: %s", path)
-			}
-			return nil
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
 		}
-		ch <- &file{path, fi.Mode()}
-		return nil
-	})
+		footers[ext] = string(b)
+	}
+	return footers, nil
 }
 
-// fileMatches determines if path matches one of the provided file patterns.
-// Patterns are assumed to be valid.
-func fileMatches(path string, patterns []string) bool {
-	for _, p := range patterns {
-		// ignore error, since we assume patterns are valid
-		if match, _ := doublestar.Match(p, path); match {
-			return true
+// splitList splits a comma separated list, dropping empty elements.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
 		}
 	}
-	return false
+	return list
 }
 
-// addLicense add a license to the file if missing.
-//
-// It returns true if the file was updated.
-func addLicense(path string, fmode os.FileMode, tmpl *template.Template, data licenseData) (bool, error) {
-	var lic []byte
-	var err error
-	lic, err = licenseHeader(path, tmpl, data)
-	if err != nil || lic == nil {
-		return false, err
+// fileListRoots returns the patterns of args, where "-" stands for the list of
+// files read from stdin, followed by the files listed in the file named list,
+// or in stdin if list is "-", and by the NUL-separated files listed in the
+// file named nulList, or in stdin if nulList is "-".
+func fileListRoots(args []string, list, nulList string, stdin io.Reader) ([]string, error) {
+	var roots []string
+	readStdin := list == "-"
+	for _, arg := range args {
+		if arg == "-" {
+			readStdin = true
+		} else {
+			roots = append(roots, arg)
+		}
 	}
-
-	b, err := ioutil.ReadFile(path)
-	if err != nil {
-		return false, err
+	if readStdin && nulList == "-" {
+		return nil, errors.New("-files-from - can't be combined with another file list read from stdin")
 	}
-	if hasLicense(b) || isGenerated(b) {
-		return false, err
+	if readStdin {
+		files, err := readFileList(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading file list from stdin: %v", err)
+		}
+		roots = append(roots, files...)
 	}
-
-	line := hashBang(b)
-	if len(line) > 0 {
-		b = b[len(line):]
-		if line[len(line)-1] != '\n' {
-			line = append(line, '\n')
+	if nulList == "-" {
+		files, err := readNulFileList(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading file list from stdin: %v", err)
 		}
-		lic = append(line, lic...)
+		roots = append(roots, files...)
 	}
-	b = append(lic, b...)
-	return true, ioutil.WriteFile(path, b, fmode)
+	for _, l := range []struct {
+		name string
+		read func(io.Reader) ([]string, error)
+	}{
+		{list, readFileList},
+		{nulList, readNulFileList},
+	} {
+		if l.name == "" || l.name == "-" {
+			continue
+		}
+		files, err := readFileListFile(l.name, l.read)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, files...)
+	}
+	return roots, nil
 }
 
-// fileHasLicense reports whether the file at path contains a license header.
-func fileHasLicense(path string) (bool, error) {
-	b, err := ioutil.ReadFile(path)
+// readFileListFile reads the list of files in the file named name with read.
+func readFileListFile(name string, read func(io.Reader) ([]string, error)) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	files, err := read(f)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("reading file list %s: %v", name, err)
 	}
-	// If generated, we count it as if it has a license.
-	return hasLicense(b) || isGenerated(b), nil
+	return files, nil
 }
 
-// licenseHeader populates the provided license template with data, and returns
-// it with the proper prefix for the file type specified by path. The file does
-// not need to actually exist, only its name is used to determine the prefix.
-func licenseHeader(path string, tmpl *template.Template, data licenseData) ([]byte, error) {
-	var lic []byte
-	var err error
-	base := strings.ToLower(filepath.Base(path))
-
-	switch fileExtension(base) {
-	case ".c", ".h", ".gv", ".java", ".scala", ".kt", ".kts":
-		lic, err = executeTemplate(tmpl, data, "/*", " * ", " */")
-	case ".js", ".mjs", ".cjs", ".jsx", ".tsx", ".css", ".scss", ".sass", ".ts":
-		lic, err = executeTemplate(tmpl, data, "/**", " * ", " */")
-	case ".cc", ".cpp", ".cs", ".go", ".hcl", ".hh", ".hpp", ".m", ".mm", ".proto", ".rs", ".swift", ".dart", ".groovy", ".v", ".sv":
-		lic, err = executeTemplate(tmpl, data, "", "// ", "")
-	case ".py", ".sh", ".yaml", ".yml", ".dockerfile", "dockerfile", ".rb", "gemfile", ".tcl", ".tf", ".bzl", ".pl", ".pp", "build", ".build", ".toml":
-		lic, err = executeTemplate(tmpl, data, "", "# ", "")
-	case ".el", ".lisp":
-		lic, err = executeTemplate(tmpl, data, "", ";; ", "")
-	case ".erl":
-		lic, err = executeTemplate(tmpl, data, "", "% ", "")
-	case ".hs", ".sql", ".sdl":
-		lic, err = executeTemplate(tmpl, data, "", "-- ", "")
-	case ".html", ".xml", ".vue", ".wxi", ".wxl", ".wxs":
-		lic, err = executeTemplate(tmpl, data, "<!--", " ", "-->")
-	case ".php":
-		lic, err = executeTemplate(tmpl, data, "", "// ", "")
-	case ".j2":
-		lic, err = executeTemplate(tmpl, data, "{#", "", "#}")
-	case ".ml", ".mli", ".mll", ".mly":
-		lic, err = executeTemplate(tmpl, data, "(**", "   ", "*)")
-	default:
-		// handle various cmake files
-		if base == "cmakelists.txt" || strings.HasSuffix(base, ".cmake.in") || strings.HasSuffix(base, ".cmake") {
-			lic, err = executeTemplate(tmpl, data, "", "# ", "")
+// readFileList reads a list of files, one per line, such as the output of
+// "git diff --name-only". Blank lines are ignored.
+func readFileList(r io.Reader) ([]string, error) {
+	var files []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if f := strings.TrimSpace(sc.Text()); f != "" {
+			files = append(files, f)
 		}
 	}
-	return lic, err
+	return files, sc.Err()
 }
 
-// fileExtension returns the file extension of name, or the full name if there
-// is no extension.
-func fileExtension(name string) string {
-	if v := filepath.Ext(name); v != "" {
-		return v
+// readNulFileList reads a list of files separated by NUL bytes, such as the
+// output of "git ls-files -z" or "find -print0". Unlike readFileList, names
+// are kept verbatim, so that they may hold spaces and newlines. Empty names
+// are ignored.
+func readNulFileList(r io.Reader) ([]string, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(string(b), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
 	}
-	return name
+	return files, nil
 }
 
-var head = []string{
-	"#!",                       // shell script
-	"<?xml",                    // XML declaratioon
-	"<!doctype",                // HTML doctype
-	"# encoding:",              // Ruby encoding
-	"# frozen_string_literal:", // Ruby interpreter instruction
-	"<?php",                    // PHP opening tag
-	"# escape",                 // Dockerfile directive https://docs.docker.com/engine/reference/builder/#parser-directives
-	"# syntax",                 // Dockerfile directive https://docs.docker.com/engine/reference/builder/#parser-directives
+// subcommands maps the name of each subcommand to its entry point, which is
+// passed the remaining command line arguments and returns the exit code.
+var subcommands = map[string]func(args []string) int{
+	"diff-trees":    diffTreesMain,
+	"drift":         driftMain,
+	"extract":       extractMain,
+	"init":          initMain,
+	"rollback":      rollbackMain,
+	"test-template": testTemplateMain,
 }
 
-func hashBang(b []byte) []byte {
-	var line []byte
-	for _, c := range b {
-		line = append(line, c)
-		if c == '\n' {
-			break
+func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+
+	flag.Parse()
+	if quiet && *verbose {
+		log.Fatal("-q and -v can't be combined")
+	}
+	if quiet {
+		logLevel = logLevelFlag(addlicense.LogWarn)
+	} else if *verbose {
+		logLevel = logLevelFlag(addlicense.LogDebug)
+	}
+	if err := loadConfig(); err != nil {
+		log.Fatal(err)
+	}
+	if err := loadIgnoreFile(); err != nil {
+		log.Fatal(err)
+	}
+	if flag.NArg() == 0 && *filesf == "" && *filesFrom == "" && !*gitStaged && !*gitTracked && *gitSince == "" && !*lspLite {
+		flag.Usage()
+		os.Exit(1)
+	}
+	detectLicense()
+	if *holder == "auto" {
+		h, source := addlicense.DetectHolder(".")
+		if h == "" {
+			log.Fatal("-c auto: no copyright holder found in the git remote, go.mod or git config")
+		}
+		// the holder is confirmed with a dry run, whose diffs are read apart
+		// from the log
+		if dryRun {
+			fmt.Printf("# copyright holder %q derived from %s\n", h, source)
+		} else {
+			logInfo("using the copyright holder %q derived from %s", h, source)
 		}
+		*holder = h
+	}
+	if _, ok := reportFormats[*format]; !ok && *format != "text" && *format != "ndjson" {
+		log.Fatalf("unknown -format %q", *format)
+	}
+	if *format == "ndjson" && dryRun {
+		log.Fatal("-format ndjson can't be combined with -n, which writes diffs to stdout")
 	}
-	first := strings.ToLower(string(line))
-	for _, h := range head {
-		if strings.HasPrefix(first, h) {
-			return line
+	if *gitAdded && !*gitStaged {
+		log.Fatal("-git-added-only requires -git-staged")
+	}
+
+	// convert -skip flags to -ignore equivalents
+	for _, s := range skipExtensionFlags {
+		ignorePatterns = append(ignorePatterns, fmt.Sprintf("**/*.%s", s))
+	}
+	for _, f := range []struct {
+		name     string
+		patterns []string
+	}{
+		{"ignore", ignorePatterns},
+		{"include", includePatterns},
+		{"include-hidden", hiddenPatterns},
+	} {
+		for _, p := range f.patterns {
+			if !doublestar.ValidatePattern(p) {
+				log.Fatalf("-%s pattern %q is not valid", f.name, p)
+			}
 		}
 	}
-	return nil
-}
 
-// go generate: ^// Code generated .* DO NOT EDIT\.$
-var goGenerated = regexp.MustCompile(`(?m)^.{1,2} Code generated .* DO NOT EDIT\.$`)
+	// with -ext, - filters stdin instead of naming a file list
+	filter := *stdinExt != ""
+	if filter && (flag.NArg() != 1 || flag.Arg(0) != "-" || *filesf != "" || *filesFrom != "") {
+		log.Fatal("-ext requires - as the only pattern, without -files or -files-from")
+	}
+	var roots []string
+	if !filter {
+		var err error
+		if roots, err = fileListRoots(flag.Args(), *filesf, *filesFrom, os.Stdin); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *noYear {
+		*year = ""
+	}
+	gitYears := *year == "git"
+	if gitYears {
+		*year = fmt.Sprint(time.Now().Year())
+	}
+
+	opts := addlicense.Options{
+		Roots:            roots,
+		Holder:           *holder,
+		Year:             *year,
+		GitYears:         gitYears,
+		License:          *license,
+		TemplateFile:     *licensef,
+		ReferenceFile:    *licenseRef,
+		SPDX:             addlicense.SPDXMode(spdx),
+		FileContributors: contributorFlags,
+		FileType:         *fileType,
+		Notice:           *notice,
+		KeepShort:        *keepShort,
+		Ignore:           ignorePatterns,
+		Include:          includePatterns,
+		Hidden:           addlicense.HiddenPolicy(hiddenPolicy),
+		IncludeHidden:    hiddenPatterns,
+		OnlyExtensions:   splitList(*onlyExt),
+		NoDefaultIgnores: noDefaultIgnores,
+		WalkVCS:          walkVCS,
+		FollowSymlinks:   followSymlinks,
+		MaxDepth:         *maxDepth,
+		MaxSize:          int64(maxSize),
+		Minified:         minified,
+		WalkWorkers:      *walkWorkers,
+		Workers:          *workers,
+		Presets:          presetFlags,
+		Markers:          markerFlags,
+		GitStaged:        *gitStaged,
+		GitTracked:       *gitTracked,
+		GitSince:         *gitSince,
+		GitAddedOnly:     *gitAdded,
+		CheckOnly:        *checkonly,
+		Remove:           *remove,
+		Replace:          *replace,
+		Forbid:           forbidFlags,
+		DryRun:           dryRun,
+		FixDuplicates:    *fixDups,
+		NormalizeYears:   addlicense.YearPolicy(yearNormalization),
+		Warn:             warnPolicy,
+		FileTimeout:      *fileTimeout,
+		TolerateErrors:   *tolerate,
+		VerifyGo:         *verifyGo,
+		Snapshot:         *snapshot,
+		OutputDir:        *outDir,
+		Backup:           backup,
+		BackupDir:        *backupDir,
+		Cache:            *cachef,
+		Licenses:         addlicense.LicenseRules(subtreeLicenses),
+		InsertAfter:      addlicense.InsertRules(insertAfter),
+		ExtStyles:        extStyles,
+		CommentStyles:    commentStyles,
+		Verbose:          *verbose,
+		LogLevel:         addlicense.LogLevel(logLevel),
+	}
+	if colorMode.enabled(os.Stderr) {
+		opts.LogStyle = paint
+	}
+	if len(footerFlags) > 0 {
+		var err error
+		if opts.Footers, err = readFooters(footerFlags); err != nil {
+			log.Fatalf("-footer: %v", err)
+		}
+	}
+	if *holdersf != "" {
+		var err error
+		if opts.HolderRules, err = addlicense.ReadHolderRules(*holdersf); err != nil {
+			log.Fatalf("-rewrite-holders: %v", err)
+		}
+	}
 
-// cargo raze: ^DO NOT EDIT! Replaced on runs of cargo-raze$
-var cargoRazeGenerated = regexp.MustCompile(`(?m)^DO NOT EDIT! Replaced on runs of cargo-raze$`)
+	if *lspLite {
+		os.Exit(serveLSPLite(os.Stdin, os.Stdout, opts))
+	}
+	if filter {
+		os.Exit(filterStdin(os.Stdin, os.Stdout, opts, *stdinExt))
+	}
 
-// isGenerated returns true if it contains a string that implies the file was
-// generated.
-func isGenerated(b []byte) bool {
-	return goGenerated.Match(b) || cargoRazeGenerated.Match(b)
-}
+	if *otelURL != "" {
+		telemetry = newOTelExporter(*otelURL)
+	}
 
-func hasLicense(b []byte) bool {
-	n := 1000
-	if len(b) < 1000 {
-		n = len(b)
+	var events *ndjsonWriter
+	if *format == "ndjson" {
+		events = newNDJSONWriter(os.Stdout)
+	}
+	if telemetry != nil || events != nil {
+		opts.OnResult = func(res addlicense.Result) {
+			telemetry.result(res)
+			if events != nil {
+				events.write(res)
+			}
+		}
+	}
+	var pp *progressPrinter
+	if progress {
+		pp = &progressPrinter{w: os.Stderr, tty: isTerminal(os.Stderr)}
+		opts.Progress = pp.update
+		opts.ProgressInterval = progressInterval
+		if pp.tty {
+			opts.ProgressInterval = progressIntervalTTY
+		}
+		opts.Logger = log.New(pp, "", log.LstdFlags)
+	}
+	report, err := addlicense.Run(context.Background(), opts)
+	if pp != nil {
+		pp.done()
+	}
+	if report == nil {
+		log.Fatal(err)
+	}
+	if events != nil && events.err != nil {
+		err = events.err
+	}
+	telemetry.record(report)
+	if oerr := appendGitHubOutputs(report); oerr != nil {
+		log.Printf("writing step outputs: %v", oerr)
+	}
+	if serr := appendGitHubStepSummary(report); serr != nil {
+		log.Printf("writing step summary: %v", serr)
+	}
+	if addlicense.LogLevel(logLevel).Enabled(addlicense.LogInfo) {
+		if werr := writeUnknownExtensions(os.Stderr, report.UnknownExtensions(), logLevel == logLevelFlag(addlicense.LogDebug)); werr != nil {
+			log.Printf("writing unknown extensions: %v", werr)
+		}
+	}
+	if werr := writeErrorSummary(os.Stderr, report); werr != nil {
+		log.Printf("writing error summary: %v", werr)
+	}
+	if *profile {
+		if perr := writeProfile(os.Stderr, report); perr != nil {
+			log.Printf("writing profile: %v", perr)
+		}
+	}
+	// ndjson events were written as files were processed
+	if *checkonly && events == nil {
+		if rerr := writeCheckResults(report, *format, *outputf, *chunk, colorMode.enabled(os.Stdout)); rerr != nil {
+			log.Printf("writing check results: %v", rerr)
+			err = rerr
+		}
+		// machine readable results written to stdout must not be mixed with
+		// annotations
+		if *annotate && (*format == "text" || *outputf != "") {
+			if aerr := writeGitHubAnnotations(os.Stdout, report); aerr != nil {
+				log.Printf("writing annotations: %v", aerr)
+				err = aerr
+			}
+		}
+	}
+	if xerr := telemetry.export(); xerr != nil {
+		log.Printf("exporting telemetry: %v", xerr)
+	}
+	failed := report.Count(addlicense.StatusError)
+	if *tolerate > 0 && failed > 0 {
+		if failed <= *tolerate {
+			log.Printf("%d file errors tolerated, up to %d allowed by -tolerate-errors", failed, *tolerate)
+		} else {
+			log.Printf("%d file errors, more than the %d allowed by -tolerate-errors", failed, *tolerate)
+		}
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+	if failed > 0 {
+		os.Exit(exitTolerated)
 	}
-	return bytes.Contains(bytes.ToLower(b[:n]), []byte("copyright")) ||
-		bytes.Contains(bytes.ToLower(b[:n]), []byte("mozilla public")) ||
-		bytes.Contains(bytes.ToLower(b[:n]), []byte("spdx-license-identifier"))
 }
+
+// exitTolerated is the exit code of runs whose only failures are file errors
+// tolerated by -tolerate-errors.
+const exitTolerated = 3
@@ -19,9 +19,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
-	"text/template"
 )
 
 func run(t *testing.T, name string, args ...string) {
@@ -89,6 +89,34 @@ func TestMultiyear(t *testing.T) {
 	run(t, "diff", samplefile, sampleLicensed)
 }
 
+func TestAutoHolderDryRun(t *testing.T) {
+	if os.Getenv("RUNME") != "" {
+		main()
+		return
+	}
+
+	tmp := tempDir(t)
+	t.Logf("tmp dir: %s", tmp)
+	if err := ioutil.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module github.com/acme/tool\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(t, "cp", "testdata/initial/file.c", filepath.Join(tmp, "file.c"))
+	cmd := exec.Command(os.Args[0],
+		"-test.run=TestAutoHolderDryRun",
+		"-l", "bsd", "-c", "auto", "-y", "2018", "-n",
+		"file.c",
+	)
+	cmd.Dir = tmp
+	cmd.Env = []string{"RUNME=1"}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if want := "# copyright holder \"acme\" derived from go.mod module github.com/acme/tool\n"; !strings.HasPrefix(string(out), want) {
+		t.Errorf("dry run output doesn't start with %q:\n%s", want, out)
+	}
+}
+
 func TestWriteErrors(t *testing.T) {
 	if os.Getenv("RUNME") != "" {
 		main()
@@ -186,6 +214,76 @@ func TestCheckFail(t *testing.T) {
 	}
 }
 
+func TestTolerateErrors(t *testing.T) {
+	if os.Getenv("RUNME") != "" {
+		main()
+		return
+	}
+
+	tmp := tempDir(t)
+	t.Logf("tmp dir: %s", tmp)
+	// a file that doesn't parse fails Go verification
+	samplefile := filepath.Join(tmp, "file.go")
+	if err := ioutil.WriteFile(samplefile, []byte("package a\nfunc {\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		tolerate string
+		code     int
+	}{
+		{"0", 1},
+		{"1", exitTolerated},
+	} {
+		cmd := exec.Command(os.Args[0],
+			"-test.run=TestTolerateErrors",
+			"-l", "apache", "-c", "Google LLC", "-y", "2018",
+			"-verify-compiles", "-tolerate-errors", tt.tolerate, samplefile,
+		)
+		cmd.Env = []string{"RUNME=1"}
+		out, _ := cmd.CombinedOutput()
+		if got := cmd.ProcessState.ExitCode(); got != tt.code {
+			t.Errorf("-tolerate-errors %s exited with code %d, want %d\n%s", tt.tolerate, got, tt.code, out)
+		}
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	if os.Getenv("RUNME") != "" {
+		main()
+		return
+	}
+
+	tmp := tempDir(t)
+	t.Logf("tmp dir: %s", tmp)
+	if err := ioutil.WriteFile(filepath.Join(tmp, "big.go"), []byte("package a\n\nvar big = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		flag string
+		want bool
+	}{
+		{"-log-level=info", true},
+		{"-log-level=warn", false},
+		{"-q", false},
+	} {
+		cmd := exec.Command(os.Args[0],
+			"-test.run=TestQuiet",
+			"-l", "apache", "-c", "Google LLC", "-y", "2018",
+			"-max-size", "10", tt.flag, tmp,
+		)
+		cmd.Env = []string{"RUNME=1"}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", tt.flag, err, out)
+		}
+		if got := strings.Contains(string(out), "skipping:"); got != tt.want {
+			t.Errorf("%s logged skipped files %v, want %v\n%s", tt.flag, got, tt.want, out)
+		}
+	}
+}
+
 func TestMPL(t *testing.T) {
 	if os.Getenv("RUNME") != "" {
 		main()
@@ -208,262 +306,129 @@ func TestMPL(t *testing.T) {
 	}
 }
 
-func createTempFile(contents string, pattern string) (*os.File, error) {
-	f, err := ioutil.TempFile("", pattern)
-	if err != nil {
-		return nil, err
+func TestGitStaged(t *testing.T) {
+	if os.Getenv("RUNME") != "" {
+		main()
+		return
 	}
-
-	if err := ioutil.WriteFile(f.Name(), []byte(contents), 0644); err != nil {
-		return nil, err
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
 	}
 
-	return f, nil
-}
-
-func TestAddLicense(t *testing.T) {
-	tmpl := template.Must(template.New("").Parse("{{.Holder}}{{.Year}}{{.SPDXID}}"))
-	data := licenseData{Holder: "H", Year: "Y", SPDXID: "S"}
-
-	tests := []struct {
-		contents     string
-		wantContents string
-		wantUpdated  bool
-	}{
-		{"", "// HYS\n\n", true},
-		{"content", "// HYS\n\ncontent", true},
-
-		// various headers that should be left intact. Many don't make
-		// sense for our temp file extension, but that doesn't matter.
-		{"#!/bin/bash\ncontent", "#!/bin/bash\n// HYS\n\ncontent", true},
-		{"<?xml version='1.0'?>\ncontent", "<?xml version='1.0'?>\n// HYS\n\ncontent", true},
-		{"<!doctype html>\ncontent", "<!doctype html>\n// HYS\n\ncontent", true},
-		{"<!DOCTYPE HTML>\ncontent", "<!DOCTYPE HTML>\n// HYS\n\ncontent", true},
-		{"# encoding: UTF-8\ncontent", "# encoding: UTF-8\n// HYS\n\ncontent", true},
-		{"# frozen_string_literal: true\ncontent", "# frozen_string_literal: true\n// HYS\n\ncontent", true},
-		{"<?php\ncontent", "<?php\n// HYS\n\ncontent", true},
-		{"# escape: `\ncontent", "# escape: `\n// HYS\n\ncontent", true},
-		{"# syntax: docker/dockerfile:1.3\ncontent", "# syntax: docker/dockerfile:1.3\n// HYS\n\ncontent", true},
-
-		// ensure files with existing license or generated files are
-		// skipped. No need to test all permutations of these, since
-		// there are specific tests below.
-		{"// Copyright 2000 Acme\ncontent", "// Copyright 2000 Acme\ncontent", false},
-		{"// Code generated by go generate; DO NOT EDIT.\ncontent", "// Code generated by go generate; DO NOT EDIT.\ncontent", false},
+	tmp := tempDir(t)
+	t.Logf("tmp dir: %s", tmp)
+	git := func(args ...string) {
+		run(t, "git", append([]string{"-C", tmp, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
 	}
-
-	for _, tt := range tests {
-		// create temp file with contents
-		f, err := createTempFile(tt.contents, "*.go")
-		if err != nil {
-			t.Error(err)
-		}
-		fi, err := f.Stat()
-		if err != nil {
-			t.Error(err)
-		}
-
-		// run addlicense
-		updated, err := addLicense(f.Name(), fi.Mode(), tmpl, data)
-		if err != nil {
-			t.Error(err)
-		}
-
-		// check results
-		if updated != tt.wantUpdated {
-			t.Errorf("addLicense with contents %q returned updated: %t, want %t", tt.contents, updated, tt.wantUpdated)
-		}
-		gotContents, err := ioutil.ReadFile(f.Name())
-		if err != nil {
-			t.Error(err)
-		}
-		if got := string(gotContents); got != tt.wantContents {
-			t.Errorf("addLicense with contents %q returned contents: %q, want %q", tt.contents, got, tt.wantContents)
-		}
-
-		// if all tests passed, cleanup temp file
-		if !t.Failed() {
-			_ = os.Remove(f.Name())
-		}
+	git("init", "-q")
+	run(t, "cp", "testdata/initial/file.c", filepath.Join(tmp, "modified.c"))
+	git("add", "modified.c")
+	git("commit", "-q", "-m", "initial")
+	if err := ioutil.WriteFile(filepath.Join(tmp, "modified.c"), []byte("int main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
 	}
-}
-
-// Test that license headers are added using the appropriate prefix for
-// different filenames and extensions.
-func TestLicenseHeader(t *testing.T) {
-	tpl := template.Must(template.New("").Parse("{{.Holder}}{{.Year}}{{.SPDXID}}"))
-	data := licenseData{Holder: "H", Year: "Y", SPDXID: "S"}
+	run(t, "cp", "testdata/initial/file.c", filepath.Join(tmp, "added.c"))
+	run(t, "cp", "testdata/initial/file.c", filepath.Join(tmp, "untracked.c"))
+	git("add", "modified.c", "added.c")
 
 	tests := []struct {
-		paths []string // paths passed to licenseHeader
-		want  string   // expected result of executing template
+		args    []string
+		changed []string // files expected to have a license header afterwards
 	}{
-		{
-			[]string{"f.unknown"},
-			"",
-		},
-		{
-			[]string{"f.c", "f.h", "f.gv", "f.java", "f.scala", "f.kt", "f.kts"},
-			"/*\n * HYS\n */\n\n",
-		},
-		{
-			[]string{"f.js", "f.mjs", "f.cjs", "f.jsx", "f.tsx", "f.css", "f.scss", "f.sass", "f.ts"},
-			"/**\n * HYS\n */\n\n",
-		},
-		{
-			[]string{"f.cc", "f.cpp", "f.cs", "f.go", "f.hcl", "f.hh", "f.hpp", "f.m", "f.mm", "f.proto",
-				"f.rs", "f.swift", "f.dart", "f.groovy", "f.v", "f.sv", "f.php"},
-			"// HYS\n\n",
-		},
-		{
-			[]string{"f.py", "f.sh", "f.yaml", "f.yml", "f.dockerfile", "dockerfile", "f.rb", "gemfile", "f.tcl", "f.tf", "f.bzl", "f.pl", "f.pp", "build"},
-			"# HYS\n\n",
-		},
-		{
-			[]string{"f.el", "f.lisp"},
-			";; HYS\n\n",
-		},
-		{
-			[]string{"f.erl"},
-			"% HYS\n\n",
-		},
-		{
-			[]string{"f.hs", "f.sql", "f.sdl"},
-			"-- HYS\n\n",
-		},
-		{
-			[]string{"f.html", "f.xml", "f.vue", "f.wxi", "f.wxl", "f.wxs"},
-			"<!--\n HYS\n-->\n\n",
-		},
-		{
-			[]string{"f.ml", "f.mli", "f.mll", "f.mly"},
-			"(**\n   HYS\n*)\n\n",
-		},
-		{
-			[]string{"cmakelists.txt", "f.cmake", "f.cmake.in"},
-			"# HYS\n\n",
-		},
-
-		// ensure matches are case insenstive
-		{
-			[]string{"F.PY", "DoCkErFiLe"},
-			"# HYS\n\n",
-		},
+		{[]string{"-git-staged", "-git-added-only"}, []string{"added.c"}},
+		{[]string{"-git-staged"}, []string{"added.c", "modified.c"}},
 	}
-
 	for _, tt := range tests {
-		for _, path := range tt.paths {
-			header, _ := licenseHeader(path, tpl, data)
-			if got := string(header); got != tt.want {
-				t.Errorf("licenseHeader(%q) returned: %q, want: %q", path, got, tt.want)
+		cmd := exec.Command(os.Args[0], append([]string{"-test.run=TestGitStaged"}, tt.args...)...)
+		cmd.Dir = tmp
+		cmd.Env = append(os.Environ(), "RUNME=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		for _, name := range []string{"added.c", "modified.c", "untracked.c"} {
+			b, err := ioutil.ReadFile(filepath.Join(tmp, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := false
+			for _, c := range tt.changed {
+				want = want || c == name
+			}
+			if got := strings.Contains(string(b), "Copyright"); got != want {
+				t.Errorf("%v: %s has license %v, want %v", tt.args, name, got, want)
 			}
 		}
 	}
 }
 
-// Test that generated files are properly recognized.
-func TestIsGenerated(t *testing.T) {
-	tests := []struct {
-		content string
-		want    bool
-	}{
-		{"", false},
-		{"Generated", false},
-		{"// Code generated by go generate; DO NOT EDIT.", true},
-		{"/*\n* Code generated by go generate; DO NOT EDIT.\n*/\n", true},
-		{"DO NOT EDIT! Replaced on runs of cargo-raze", true},
+func TestFileListRoots(t *testing.T) {
+	dir := tempDir(t)
+	list := filepath.Join(dir, "files.txt")
+	if err := ioutil.WriteFile(list, []byte("c.go\n\nd.go\n"), 0644); err != nil {
+		t.Fatal(err)
 	}
-
-	for _, tt := range tests {
-		b := []byte(tt.content)
-		if got := isGenerated(b); got != tt.want {
-			t.Errorf("isGenerated(%q) returned %v, want %v", tt.content, got, tt.want)
-		}
+	nulList := filepath.Join(dir, "files.bin")
+	if err := ioutil.WriteFile(nulList, []byte("e f.go\x00g\nh.go\x00\x00"), 0644); err != nil {
+		t.Fatal(err)
 	}
-}
+	stdin := "a.go\r\n  b/c.go\n\n"
 
-// Test that existing license headers are identified.
-func TestHasLicense(t *testing.T) {
 	tests := []struct {
-		content string
-		want    bool
+		args    []string
+		list    string
+		nulList string
+		stdin   string
+		want    []string
 	}{
-		{"", false},
-		{"This is my license", false},
-		{"This code is released into the public domain.", false},
-		{"SPDX: MIT", false},
-
-		{"Copyright 2000", true},
-		{"CoPyRiGhT 2000", true},
-		{"Subject to the terms of the Mozilla Public License", true},
-		{"SPDX-License-Identifier: MIT", true},
-		{"spdx-license-identifier: MIT", true},
+		{[]string{"x", "y"}, "", "", stdin, []string{"x", "y"}},
+		{[]string{"x", "-"}, "", "", stdin, []string{"x", "a.go", "b/c.go"}},
+		{nil, "-", "", stdin, []string{"a.go", "b/c.go"}},
+		{[]string{"x"}, list, "", stdin, []string{"x", "c.go", "d.go"}},
+		{[]string{"x"}, "", nulList, stdin, []string{"x", "e f.go", "g\nh.go"}},
+		{nil, list, "-", " a b.go \x00c\n.go", []string{" a b.go ", "c\n.go", "c.go", "d.go"}},
 	}
-
 	for _, tt := range tests {
-		b := []byte(tt.content)
-		if got := hasLicense(b); got != tt.want {
-			t.Errorf("hasLicense(%q) returned %v, want %v", tt.content, got, tt.want)
+		got, err := fileListRoots(tt.args, tt.list, tt.nulList, strings.NewReader(tt.stdin))
+		if err != nil {
+			t.Fatalf("fileListRoots(%q, %q, %q): %v", tt.args, tt.list, tt.nulList, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("fileListRoots(%q, %q, %q) = %q, want %q", tt.args, tt.list, tt.nulList, got, tt.want)
 		}
 	}
+
+	// stdin holds a single list
+	if _, err := fileListRoots([]string{"-"}, "", "-", strings.NewReader(stdin)); err == nil {
+		t.Error("fileListRoots of - with -files-from - returned no error")
+	}
 }
 
-func TestFileMatches(t *testing.T) {
+func TestSizeFlag(t *testing.T) {
 	tests := []struct {
-		pattern   string
-		path      string
-		wantMatch bool
+		value string
+		want  int64
 	}{
-		// basic single directory patterns
-		{"", "file.c", false},
-		{"*.c", "file.h", false},
-		{"*.c", "file.c", true},
-
-		// subdirectory patterns
-		{"*.c", "vendor/file.c", false},
-		{"**/*.c", "vendor/file.c", true},
-		{"vendor/**", "vendor/file.c", true},
-		{"vendor/**/*.c", "vendor/file.c", true},
-		{"vendor/**/*.c", "vendor/a/b/file.c", true},
-
-		// single character "?" match
-		{"*.?", "file.c", true},
-		{"*.?", "file.go", false},
-		{"*.??", "file.c", false},
-		{"*.??", "file.go", true},
-
-		// character classes - sets and ranges
-		{"*.[ch]", "file.c", true},
-		{"*.[ch]", "file.h", true},
-		{"*.[ch]", "file.ch", false},
-		{"*.[a-z]", "file.c", true},
-		{"*.[a-z]", "file.h", true},
-		{"*.[a-z]", "file.go", false},
-		{"*.[a-z]", "file.R", false},
-
-		// character classes - negations
-		{"*.[^ch]", "file.c", false},
-		{"*.[^ch]", "file.h", false},
-		{"*.[^ch]", "file.R", true},
-		{"*.[!ch]", "file.c", false},
-		{"*.[!ch]", "file.h", false},
-		{"*.[!ch]", "file.R", true},
-
-		// comma-separated alternative matches
-		{"*.{c,go}", "file.c", true},
-		{"*.{c,go}", "file.go", true},
-		{"*.{c,go}", "file.h", false},
-
-		// negating alternative matches
-		{"*.[^{c,go}]", "file.c", false},
-		{"*.[^{c,go}]", "file.go", false},
-		{"*.[^{c,go}]", "file.h", true},
+		{"1024", 1024},
+		{"5MB", 5000000},
+		{"5mb", 5000000},
+		{"512KiB", 512 << 10},
+		{"1.5G", 3 << 29},
+		{"100 B", 100},
 	}
-
 	for _, tt := range tests {
-		patterns := []string{tt.pattern}
-		if got := fileMatches(tt.path, patterns); got != tt.wantMatch {
-			t.Errorf("fileMatches(%q, %q) returned %v, want %v", tt.path, patterns, got, tt.wantMatch)
+		var s sizeFlag
+		if err := s.Set(tt.value); err != nil {
+			t.Errorf("Set(%q): %v", tt.value, err)
+			continue
+		}
+		if int64(s) != tt.want {
+			t.Errorf("Set(%q) stored %d, want %d", tt.value, s, tt.want)
+		}
+	}
+	for _, value := range []string{"", "MB", "-1K", "five"} {
+		var s sizeFlag
+		if err := s.Set(value); err == nil {
+			t.Errorf("Set(%q) returned no error", value)
 		}
 	}
 }
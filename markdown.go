@@ -0,0 +1,115 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// maxMarkdownRows is the maximum number of files listed per table of the
+// Markdown summary, which must fit in a pull request comment.
+const maxMarkdownRows = 100
+
+// writeMarkdown writes the findings of report to w as a Markdown summary,
+// ready to be posted as a pull request comment: one table of files per kind
+// of finding. When running in GitHub Actions, files link to their contents at
+// the commit being checked.
+func writeMarkdown(w io.Writer, report *addlicense.Report) error {
+	var b strings.Builder
+	b.WriteString("## addlicense\n\n")
+	writeMarkdownFindings(&b, report)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownFindings writes to b the number of files of report needing
+// attention, followed by one table of files per kind of finding.
+func writeMarkdownFindings(b *strings.Builder, report *addlicense.Report) {
+	total := 0
+	for _, rule := range sarifRules {
+		total += report.Count(rule.status)
+	}
+	if total == 0 {
+		b.WriteString("All files have the expected license headers.\n")
+		return
+	}
+	fmt.Fprintf(b, "%d files need attention.\n", total)
+
+	link := fileLinker()
+	for _, rule := range sarifRules {
+		paths := report.Paths(rule.status)
+		if len(paths) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "\n### %s (%d)\n\n%s\n\n| File |\n| --- |\n", rule.ShortDescription.Text, len(paths), rule.Help.Text)
+		for i, path := range paths {
+			if i == maxMarkdownRows {
+				fmt.Fprintf(b, "| ... and %d more |\n", len(paths)-i)
+				break
+			}
+			fmt.Fprintf(b, "| %s |\n", link(filepath.ToSlash(filepath.Clean(path))))
+		}
+	}
+}
+
+// fileLinker returns a function formatting a path as Markdown: a link to the
+// file at the commit being checked when running in GitHub Actions, or code
+// otherwise.
+func fileLinker() func(path string) string {
+	server, repo, sha := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_SHA")
+	if server == "" || repo == "" || sha == "" {
+		return codeSpan
+	}
+	return func(path string) string {
+		if filepath.IsAbs(path) {
+			return codeSpan(path)
+		}
+		segments := strings.Split(strings.TrimPrefix(path, "./"), "/")
+		for i, s := range segments {
+			segments[i] = url.PathEscape(s)
+		}
+		return fmt.Sprintf("[%s](%s/%s/blob/%s/%s)", codeSpan(path), server, repo, sha, strings.Join(segments, "/"))
+	}
+}
+
+// codeSpan formats s as Markdown code within a table cell. The code span is
+// delimited by more backticks than the longest run of backticks in s, and
+// pipes are escaped so as not to end the cell.
+func codeSpan(s string) string {
+	longest, run := 0, 0
+	for _, c := range s {
+		if c != '`' {
+			run = 0
+			continue
+		}
+		if run++; run > longest {
+			longest = run
+		}
+	}
+	// code starting or ending with a backtick must be padded with a space,
+	// which is stripped when rendering
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	fence := strings.Repeat("`", longest+1)
+	return fence + strings.ReplaceAll(s, "|", `\|`) + fence
+}
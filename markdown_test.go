@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	for _, env := range []string{"GITHUB_SERVER_URL", "GITHUB_REPOSITORY", "GITHUB_SHA"} {
+		defer os.Setenv(env, os.Getenv(env))
+	}
+	os.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	os.Setenv("GITHUB_REPOSITORY", "acme/repo")
+	os.Setenv("GITHUB_SHA", "abc123")
+
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a/missing.go", Status: addlicense.StatusMissing},
+		{Path: "a/ok.go", Status: addlicense.StatusOK},
+		{Path: "b/dup.py", Status: addlicense.StatusDuplicate},
+		{Path: "c d/#1 (x).go", Status: addlicense.StatusMissing},
+	}}
+	var out strings.Builder
+	if err := writeMarkdown(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"3 files need attention.\n",
+		"\n### Source file is missing a license header (2)\n",
+		"| [`a/missing.go`](https://github.com/acme/repo/blob/abc123/a/missing.go) |\n",
+		"| [`c d/#1 (x).go`](https://github.com/acme/repo/blob/abc123/c%20d/%231%20%28x%29.go) |\n",
+		"\n### License header is stacked twice (1)\n",
+		"| [`b/dup.py`](https://github.com/acme/repo/blob/abc123/b/dup.py) |\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeMarkdown output doesn't contain %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "ok.go") {
+		t.Errorf("writeMarkdown listed a file with a license header:\n%s", out.String())
+	}
+
+	os.Setenv("GITHUB_SHA", "")
+	out.Reset()
+	if err := writeMarkdown(&out, &addlicense.Report{Results: []addlicense.Result{{Path: "a|b.go", Status: addlicense.StatusMissing}}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "| `a\\|b.go` |\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("writeMarkdown output doesn't contain %q:\n%s", want, out.String())
+	}
+
+	for path, want := range map[string]string{
+		"a.go":    "`a.go`",
+		"a`b.go":  "``a`b.go``",
+		"a``b.go": "```a``b.go```",
+		"`a.go":   "`` `a.go ``",
+	} {
+		if got := codeSpan(path); got != want {
+			t.Errorf("codeSpan(%q) returned %q, want %q", path, got, want)
+		}
+	}
+}
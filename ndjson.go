@@ -0,0 +1,69 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// ndjsonEvent is the event written by -format ndjson for each file, as soon
+// as it is processed.
+type ndjsonEvent struct {
+	Time     time.Time         `json:"time"`
+	Action   addlicense.Status `json:"action"`
+	Path     string            `json:"path"`
+	Duration float64           `json:"duration_ms"`
+	License  string            `json:"license,omitempty"`
+	Holder   string            `json:"holder,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// ndjsonWriter writes the outcome of each file as a JSON event on a line of
+// its own, for log aggregation and monitoring of long runs. Its write method
+// is meant to be the OnResult function of a run, whose calls don't overlap.
+type ndjsonWriter struct {
+	enc *json.Encoder
+	err error // first error writing an event
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+// write writes the event of res. Write errors are logged once, and the
+// following events are dropped.
+func (nw *ndjsonWriter) write(res addlicense.Result) {
+	ev := ndjsonEvent{
+		Time:     time.Now().UTC(),
+		Action:   res.Status,
+		Path:     filepath.ToSlash(filepath.Clean(res.Path)),
+		Duration: float64(res.Duration) / float64(time.Millisecond),
+		License:  res.License,
+		Holder:   res.Holder,
+		Error:    resultError(res),
+	}
+	if nw.err != nil {
+		return
+	}
+	if nw.err = nw.enc.Encode(ev); nw.err != nil {
+		log.Printf("writing events: %v", nw.err)
+	}
+}
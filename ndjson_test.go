@@ -0,0 +1,61 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	var out strings.Builder
+	events := newNDJSONWriter(&out)
+	events.write(addlicense.Result{Path: "a/new.go", Status: addlicense.StatusModified, Duration: 1500 * time.Microsecond})
+	events.write(addlicense.Result{Path: "a/missing.go", Status: addlicense.StatusMissing, Err: addlicense.ErrMissingLicense})
+	events.write(addlicense.Result{Path: "b/ok.py", Status: addlicense.StatusOK, License: "MIT", Holder: "Acme"})
+	events.write(addlicense.Result{Path: "b/broken.py", Status: addlicense.StatusError, Err: errors.New("permission denied")})
+	if events.err != nil {
+		t.Fatal(events.err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("wrote %d lines, want 4:\n%s", len(lines), out.String())
+	}
+	want := []ndjsonEvent{
+		{Action: addlicense.StatusModified, Path: "a/new.go", Duration: 1.5},
+		{Action: addlicense.StatusMissing, Path: "a/missing.go"},
+		{Action: addlicense.StatusOK, Path: "b/ok.py", License: "MIT", Holder: "Acme"},
+		{Action: addlicense.StatusError, Path: "b/broken.py", Error: "permission denied"},
+	}
+	for i, line := range lines {
+		var ev ndjsonEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %d: %v: %s", i, err, line)
+		}
+		if ev.Time.IsZero() {
+			t.Errorf("line %d has no time: %s", i, line)
+		}
+		ev.Time = time.Time{}
+		if ev != want[i] {
+			t.Errorf("line %d is %+v, want %+v", i, ev, want[i])
+		}
+	}
+}
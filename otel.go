@@ -0,0 +1,387 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// Stages of a run whose durations are reported to OpenTelemetry.
+const (
+	stageWalk   = "walk"
+	stageDetect = "detect"
+	stageWrite  = "write"
+)
+
+// telemetry records the traces and metrics of the run, if enabled with the
+// -otel-endpoint flag.
+var telemetry *otelExporter
+
+// otelBatchSize is the number of spans sent per request while the run goes
+// on, as by the batch span processor of the OpenTelemetry SDKs, so that large
+// trees neither hold every span in memory nor exceed the request size limits
+// of collectors.
+const otelBatchSize = 512
+
+// otelExporter records spans, counters and stage durations of a run, and
+// exports them to an OpenTelemetry collector using the JSON encoding of
+// OTLP/HTTP. Spans are sent in batches of otelBatchSize, the rest of them and
+// the metrics once the run is over. All methods of a nil *otelExporter are
+// no-ops, so that callers don't need to check whether telemetry is enabled.
+type otelExporter struct {
+	endpoint string
+	client   *http.Client
+	traceID  string
+	rootID   string
+	start    time.Time
+
+	mu        sync.Mutex
+	spans     []otelSpan // spans not sent yet
+	err       error      // first error sending a batch of spans
+	counters  map[string]int64
+	durations map[string]time.Duration // cumulated duration of each stage
+}
+
+type otelSpan struct {
+	id         string
+	parent     string
+	name       string
+	start, end time.Time
+	attrs      []otlpKeyValue
+}
+
+// newOTelExporter returns an exporter sending data to the OTLP/HTTP collector
+// at the base URL endpoint.
+func newOTelExporter(endpoint string) *otelExporter {
+	return &otelExporter{
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		client:    &http.Client{Timeout: 10 * time.Second},
+		traceID:   randomID(16),
+		rootID:    randomID(8),
+		start:     time.Now(),
+		counters:  make(map[string]int64),
+		durations: make(map[string]time.Duration),
+	}
+}
+
+// span records a span named name, child of the span of the whole run.
+func (e *otelExporter) span(name string, start, end time.Time) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	if name == stageWalk {
+		e.durations[stageWalk] += end.Sub(start)
+	}
+	e.mu.Unlock()
+	e.add(otelSpan{id: randomID(8), parent: e.rootID, name: name, start: start, end: end})
+}
+
+// result records the process span of the file described by res, with its
+// detect and write child spans. It is meant to be called as each file is
+// processed, through Options.OnResult.
+func (e *otelExporter) result(res addlicense.Result) {
+	if e == nil {
+		return
+	}
+	end := res.Start.Add(res.Duration)
+	detectEnd := end
+	if !res.WriteStart.IsZero() {
+		detectEnd = res.WriteStart
+	}
+	process := otelSpan{
+		id:     randomID(8),
+		parent: e.rootID,
+		name:   "process",
+		start:  res.Start,
+		end:    end,
+		attrs:  []otlpKeyValue{{"file.path", otlpAnyValue{res.Path}}},
+	}
+	spans := []otelSpan{process, {id: randomID(8), parent: process.id, name: stageDetect, start: res.Start, end: detectEnd}}
+	e.mu.Lock()
+	e.durations[stageDetect] += detectEnd.Sub(res.Start)
+	if !res.WriteStart.IsZero() {
+		spans = append(spans, otelSpan{id: randomID(8), parent: process.id, name: stageWrite, start: res.WriteStart, end: res.WriteEnd})
+		e.durations[stageWrite] += res.WriteEnd.Sub(res.WriteStart)
+	}
+	e.mu.Unlock()
+	e.add(spans...)
+}
+
+// add records spans, and sends them with the spans recorded before once they
+// fill a batch. Errors are reported by export.
+func (e *otelExporter) add(spans ...otelSpan) {
+	e.mu.Lock()
+	e.spans = append(e.spans, spans...)
+	var batch []otelSpan
+	if len(e.spans) >= otelBatchSize {
+		batch, e.spans = e.spans, nil
+	}
+	e.mu.Unlock()
+	if batch == nil {
+		return
+	}
+	if err := e.post("/v1/traces", e.traces(batch)); err != nil {
+		e.mu.Lock()
+		if e.err == nil {
+			e.err = err
+		}
+		e.mu.Unlock()
+	}
+}
+
+// count adds n to the counter named name.
+func (e *otelExporter) count(name string, n int64) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.counters[name] += n
+}
+
+// record records the walk span and the counters of the run described by
+// report. The spans of each file are recorded by result as it is processed.
+func (e *otelExporter) record(report *addlicense.Report) {
+	if e == nil {
+		return
+	}
+	e.span(stageWalk, report.Start, report.WalkEnd)
+	e.count("addlicense.files.processed", int64(len(report.Results)))
+	e.count("addlicense.files.missing", int64(report.Count(addlicense.StatusMissing)))
+	e.count("addlicense.files.modified", int64(report.Count(addlicense.StatusModified)))
+	e.count("addlicense.errors", int64(report.Count(addlicense.StatusError)))
+}
+
+// export sends the spans not sent yet, the span of the whole run and the
+// metrics to the collector. It returns the first error sending spans, if
+// any, including those of earlier batches.
+func (e *otelExporter) export() error {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	root := otelSpan{id: e.rootID, name: "addlicense", start: e.start, end: now}
+	spans := append(e.spans, root)
+	e.spans = nil
+	for len(spans) > 0 {
+		n := len(spans)
+		if n > otelBatchSize {
+			n = otelBatchSize
+		}
+		if err := e.post("/v1/traces", e.traces(spans[:n])); err != nil {
+			return err
+		}
+		spans = spans[n:]
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return e.post("/v1/metrics", e.metrics(now))
+}
+
+func (e *otelExporter) post(path string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.endpoint+path, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: unexpected status %s", e.endpoint+path, resp.Status)
+	}
+	return nil
+}
+
+// The types below follow the JSON encoding of the OTLP protocol, see
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding. Note that
+// 64 bit integers are encoded as strings.
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsInt             string         `json:"asInt,omitempty"`
+	AsDouble          *float64       `json:"asDouble,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+type otlpMetric struct {
+	Name string  `json:"name"`
+	Unit string  `json:"unit"`
+	Sum  otlpSum `json:"sum"`
+}
+
+// otlpSpanKindInternal and otlpCumulative are the OTLP enum values for
+// internal spans and cumulative aggregation.
+const (
+	otlpSpanKindInternal = 1
+	otlpCumulative       = 2
+)
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func (e *otelExporter) resource() otlpResource {
+	name := os.Getenv("OTEL_SERVICE_NAME")
+	if name == "" {
+		name = "addlicense"
+	}
+	return otlpResource{Attributes: []otlpKeyValue{{"service.name", otlpAnyValue{name}}}}
+}
+
+var otlpScopeName = otlpScope{Name: "github.com/google/addlicense"}
+
+// traces returns the payload of a request sending batch.
+func (e *otelExporter) traces(batch []otelSpan) interface{} {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		spans = append(spans, otlpSpan{
+			TraceID:           e.traceID,
+			SpanID:            s.id,
+			ParentSpanID:      s.parent,
+			Name:              s.name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+			Attributes:        s.attrs,
+		})
+	}
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": e.resource(),
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": otlpScopeName,
+				"spans": spans,
+			}},
+		}},
+	}
+}
+
+func (e *otelExporter) metrics(now time.Time) interface{} {
+	var metrics []otlpMetric
+	var names []string
+	for name := range e.counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		metrics = append(metrics, otlpMetric{
+			Name: name,
+			Unit: "1",
+			Sum: otlpSum{
+				DataPoints: []otlpDataPoint{{
+					StartTimeUnixNano: unixNano(e.start),
+					TimeUnixNano:      unixNano(now),
+					AsInt:             strconv.FormatInt(e.counters[name], 10),
+				}},
+				AggregationTemporality: otlpCumulative,
+				IsMonotonic:            true,
+			},
+		})
+	}
+
+	var points []otlpDataPoint
+	for _, stage := range []string{stageWalk, stageDetect, stageWrite} {
+		seconds := e.durations[stage].Seconds()
+		points = append(points, otlpDataPoint{
+			Attributes:        []otlpKeyValue{{"stage", otlpAnyValue{stage}}},
+			StartTimeUnixNano: unixNano(e.start),
+			TimeUnixNano:      unixNano(now),
+			AsDouble:          &seconds,
+		})
+	}
+	metrics = append(metrics, otlpMetric{
+		Name: "addlicense.stage.duration",
+		Unit: "s",
+		Sum: otlpSum{
+			DataPoints:             points,
+			AggregationTemporality: otlpCumulative,
+			IsMonotonic:            true,
+		},
+	})
+
+	return map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource": e.resource(),
+			"scopeMetrics": []interface{}{map[string]interface{}{
+				"scope":   otlpScopeName,
+				"metrics": metrics,
+			}},
+		}},
+	}
+}
+
+// randomID returns n random bytes encoded as hexadecimal, as used for trace
+// and span IDs.
+func randomID(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestOTelExporter(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies[r.URL.Path] = string(b)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	e := newOTelExporter(srv.URL + "/")
+	start := time.Now()
+	report := &addlicense.Report{
+		Results: []addlicense.Result{
+			{Path: "a.go", Status: addlicense.StatusModified, Start: start, Duration: 3 * time.Millisecond, WriteStart: start.Add(time.Millisecond), WriteEnd: start.Add(2 * time.Millisecond)},
+			{Path: "b.go", Status: addlicense.StatusMissing, Err: addlicense.ErrMissingLicense},
+			{Path: "c.go", Status: addlicense.StatusError, Err: errors.New("permission denied")},
+		},
+		Start:   start,
+		WalkEnd: start.Add(time.Second),
+		End:     start.Add(2 * time.Second),
+	}
+	for _, res := range report.Results {
+		e.result(res)
+	}
+	e.record(report)
+	processID := e.spans[0].id
+	if err := e.export(); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string][]string{
+		"/v1/traces": {
+			`"name":"addlicense"`,
+			`"name":"walk"`,
+			`"parentSpanId":"` + e.rootID + `","name":"process"`,
+			`"parentSpanId":"` + processID + `","name":"detect","kind":1,"startTimeUnixNano":"` + unixNano(start) + `","endTimeUnixNano":"` + unixNano(start.Add(time.Millisecond)) + `"`,
+			`"parentSpanId":"` + processID + `","name":"write","kind":1,"startTimeUnixNano":"` + unixNano(start.Add(time.Millisecond)) + `","endTimeUnixNano":"` + unixNano(start.Add(2*time.Millisecond)) + `"`,
+			`"attributes":[{"key":"file.path","value":{"stringValue":"a.go"}}]`,
+			`"traceId":"` + e.traceID + `"`,
+		},
+		"/v1/metrics": {
+			`"name":"addlicense.files.processed","unit":"1","sum":{"dataPoints":[{"startTimeUnixNano"`,
+			`"asInt":"3"`,
+			`"name":"addlicense.files.missing"`,
+			`"name":"addlicense.files.modified"`,
+			`"name":"addlicense.errors"`,
+			`"name":"addlicense.stage.duration"`,
+			`"stringValue":"detect"`,
+		},
+	} {
+		for _, w := range want {
+			if !strings.Contains(bodies[path], w) {
+				t.Errorf("%s payload doesn't contain %s:\n%s", path, w, bodies[path])
+			}
+		}
+	}
+
+	// a nil exporter records nothing
+	var nilExporter *otelExporter
+	nilExporter.result(report.Results[0])
+	nilExporter.record(report)
+	if err := nilExporter.export(); err != nil {
+		t.Errorf("export of nil exporter returned %v", err)
+	}
+}
+
+func TestOTelExporterBatches(t *testing.T) {
+	var mu sync.Mutex
+	var batches []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		batches = append(batches, strings.Count(string(b), `"spanId"`))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	e := newOTelExporter(srv.URL)
+	start := time.Now()
+	const files = 1000
+	for i := 0; i < files; i++ {
+		e.result(addlicense.Result{Path: fmt.Sprintf("%d.go", i), Start: start, Duration: time.Millisecond})
+	}
+	mu.Lock()
+	sent := len(batches)
+	mu.Unlock()
+	if sent == 0 {
+		t.Error("no spans were sent before the end of the run")
+	}
+	if err := e.export(); err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, n := range batches {
+		if n > otelBatchSize+2 {
+			t.Errorf("a request sent %d spans, more than a batch of %d", n, otelBatchSize)
+		}
+		total += n
+	}
+	// a process and a detect span per file, and the span of the run
+	if want := 2*files + 1; total != want {
+		t.Errorf("sent %d spans in %d requests, want %d", total, len(batches), want)
+	}
+}
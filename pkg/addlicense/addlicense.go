@@ -0,0 +1,1537 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package addlicense ensures source code files have copyright license headers.
+// It implements the addlicense command, and can be used by other Go tools to
+// add or check license headers without running the command.
+package addlicense
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	doublestar "github.com/bmatcuk/doublestar/v4"
+	"golang.org/x/sync/errgroup"
+)
+
+// Options configures a run of Run.
+type Options struct {
+	// Roots lists the files and directories to process. Directories are
+	// processed recursively.
+	Roots []string
+
+	// Holder is the copyright holder.
+	Holder string
+	// Year is the copyright year(s). It is omitted from license headers if
+	// empty.
+	Year string
+	// License is the license type: an SPDX identifier such as "Apache-2.0",
+	// or one of the legacy names "apache", "mit", "mpl", "cc0" or
+	// "unlicense".
+	License string
+	// TemplateFile is the path of a custom license template. If set, it is
+	// used instead of the template of License.
+	TemplateFile string
+	// ReferenceFile is the path of a file whose existing license header
+	// is used as the template instead, with the years of its copyright
+	// statements replaced with Year.
+	ReferenceFile string
+	// SPDX controls whether license headers include an SPDX identifier.
+	SPDX SPDXMode
+	// KeepShort recognizes existing short headers, a copyright statement
+	// followed by an SPDX identifier, as complete: Replace renders them with
+	// the short template rather than with the full license text.
+	KeepShort bool
+
+	// FileContributors and FileType fill out the SPDX-FileContributor and
+	// SPDX-FileType tags of headers rendered with SPDXTags.
+	FileContributors []string
+	FileType         string
+
+	// Notice is an additional notice, such as an export control statement,
+	// a trademark notice or a classification label, that license headers
+	// hold after the license text, separated by a blank line. Templates may
+	// place it elsewhere with {{.Notice}}. Existing headers are recognized
+	// with or without it.
+	Notice string
+
+	// Footers maps file extensions, such as "c", or "*" for all files, to the
+	// template of a license footer required at the end of those files, which
+	// is rendered like license headers.
+	Footers map[string]string
+
+	// Ignore lists doublestar patterns of files to ignore.
+	Ignore []string
+	// Include, if set, restricts processing to the files matching one of
+	// these doublestar patterns, before Ignore is applied.
+	Include []string
+	// OnlyExtensions, if set, restricts processing to the files with one of
+	// these extensions, such as "go" or ".py". Files without extension are
+	// matched by name, such as "Dockerfile".
+	OnlyExtensions []string
+	// Hidden defines whether hidden files, and the files of hidden
+	// directories, are processed.
+	Hidden HiddenPolicy
+	// IncludeHidden lists doublestar patterns of hidden files processed even
+	// if Hidden skips them, such as ".github/**". The keep patterns of presets
+	// apply too.
+	IncludeHidden []string
+	// MaxDepth, if positive, limits the depth of the files processed below
+	// each root: 1 only processes the files directly in the root directories.
+	MaxDepth int
+	// Minified processes minified JavaScript and CSS files, which are
+	// skipped by default, see isMinified: headers in minified bundles break
+	// their source maps.
+	Minified bool
+	// MaxSize, if positive, is the size in bytes above which files are
+	// skipped, such as datasets or bundles checked into a repository.
+	MaxSize int64
+	// WalkWorkers is the number of directory subtrees walked concurrently.
+	// Values below 2 walk the roots sequentially, which makes the choice of
+	// the path processed among several naming the same file deterministic.
+	WalkWorkers int
+	// FollowSymlinks walks the directories that symbolic links point to,
+	// which are skipped otherwise. Directories reached twice, such as through
+	// a link to one of their parents, are walked once. Symbolic links to
+	// files are always processed, once per target.
+	FollowSymlinks bool
+	// NoDefaultIgnores walks the directories of DefaultIgnoredDirs too, which
+	// are skipped by default.
+	NoDefaultIgnores bool
+	// WalkVCS walks the version control metadata directories of VCSDirs too,
+	// which are skipped by default, regardless of NoDefaultIgnores and Hidden.
+	WalkVCS bool
+	// Presets lists the names of bundled file patterns to apply, see
+	// PresetNames.
+	Presets []string
+	// Markers lists additional phrases identifying an existing license
+	// header, besides mentions of a copyright, an SPDX identifier or a
+	// public domain dedication.
+	Markers []string
+	// GitStaged restricts processing to the files staged in the git index
+	// that match Roots, or all staged files if Roots is empty.
+	GitStaged bool
+	// GitAddedOnly further restricts GitStaged to newly added files.
+	GitAddedOnly bool
+	// GitTracked restricts processing to the files tracked by git that match
+	// Roots, or all tracked files if Roots is empty, so that build artifacts
+	// and untracked files are never touched.
+	GitTracked bool
+	// GitSince, if set, restricts processing to the files that match Roots,
+	// or all files if Roots is empty, added or modified since the merge base
+	// of the git ref GitSince and HEAD, including uncommitted changes.
+	GitSince string
+	// GitYears derives the copyright years of each added license header from
+	// the git history of its file: the year of its first commit, up to the
+	// year of its last one. Year is used for files without history.
+	GitYears bool
+
+	// CheckOnly only verifies the presence of license headers, without
+	// modifying any file. Files missing one fail with ErrMissingLicense.
+	CheckOnly bool
+	// Replace, if set, is the license type whose existing license headers are
+	// replaced with headers of License, preserving their holder and years.
+	// Missing license headers are added as usual.
+	Replace string
+	// FixDuplicates removes the redundant copy of license headers stacked
+	// twice, instead of adding missing ones.
+	FixDuplicates bool
+	// Remove strips existing license headers instead of adding missing ones.
+	Remove bool
+	// NormalizeYears rewrites the years of existing license headers.
+	NormalizeYears YearPolicy
+	// HolderRules, if set, rewrite the holder and years of existing license
+	// headers instead of adding missing ones.
+	HolderRules []HolderRule
+	// Warn lists the rules downgrading file errors to warnings.
+	Warn []WarnRule
+	// TolerateErrors is the number of files that may fail with StatusError
+	// without failing the run, so that runs over flaky file systems report
+	// their findings. Once more files fail, Run returns the error of the
+	// first file exceeding the limit.
+	TolerateErrors int
+	// ExtStyles maps file extensions, such as "lua", or names of files
+	// without extension, to the names of the comment styles of those files,
+	// as listed by CommentStyleNames. They take precedence over the built-in
+	// comment styles.
+	ExtStyles map[string]string
+	// CommentStyles maps file extensions, or names of files without
+	// extension, to the definitions of custom comment styles, for file types
+	// none of the named comment styles fits: either the prefix of line
+	// comments, such as "# ", or the comma-separated start, line prefix and
+	// end of block comments, such as "/*, * , */". They take precedence over
+	// ExtStyles.
+	CommentStyles map[string]string
+	// Licenses, if set, assign other licenses than License to the files
+	// matching their patterns: the first matching rule applies. In check
+	// only mode, the existing license headers of those files must be headers
+	// of the assigned license, or fail with ErrWrongLicense.
+	Licenses []LicenseRule
+	// InsertAfter, if set, places the license headers added to the files
+	// matching their patterns after the first line matching their regular
+	// expression: the first matching rule applies. Files without such a
+	// line get their header where it would go otherwise.
+	InsertAfter []InsertRule
+	// Forbid lists license types, such as "GPL-3.0", whose license headers
+	// fail files with ErrForbiddenLicense in check only mode. Each also
+	// forbids its variants, such as "GPL-3.0-or-later".
+	Forbid []string
+	// FileTimeout, if positive, is the maximum time spent processing a
+	// file, such as a hung network file, before failing it with
+	// ErrFileTimeout. Files failed this way are left unmodified, and files
+	// already being written when it expires are waited for.
+	FileTimeout time.Duration
+	// Workers is the number of files processed concurrently, DefaultWorkers
+	// if zero.
+	Workers int
+	// QueueSize is the number of files found by the walk that may wait for a
+	// worker, DefaultQueueSize if zero. The walk pauses while the queue is
+	// full, so that it doesn't outpace slow storage.
+	QueueSize int
+	// Progress, if set, is called with the progress of the run every
+	// ProgressInterval, DefaultProgressInterval if zero, and once more when
+	// all files are processed. Calls don't overlap.
+	Progress         func(Progress)
+	ProgressInterval time.Duration
+	// OnResult, if set, is called with the outcome of each file as soon as
+	// it is processed, in no particular order. Calls don't overlap.
+	OnResult func(Result)
+
+	// VerifyGo verifies that the updates of Go files keep them valid: they
+	// must parse, keep their build constraints in effect and stay gofmt
+	// formatted. Files failing verification are left untouched and fail.
+	VerifyGo bool
+
+	// Snapshot is a directory where the original contents of the modified
+	// files are saved, along with a manifest, so that Rollback can restore
+	// them. It must not hold a snapshot already.
+	Snapshot string
+	// Backup saves the original contents of files before they are first
+	// modified: next to them, with the BackupSuffix suffix, or in BackupDir
+	// if set, which also enables backups. Backups of earlier runs are
+	// overwritten.
+	Backup bool
+	// OutputDir, if set, is a directory where the files processed by the
+	// run are written as soon as processed, with their updates, at their
+	// paths relative to the current directory, leaving the files themselves
+	// untouched. Ignored files and files that failed are not written. It is
+	// created if missing, and skipped by the walk. It is not written in dry
+	// runs.
+	OutputDir string
+	// BackupDir is a directory where backups are saved, at the paths of the
+	// files relative to the current directory. It is created if missing, and
+	// skipped by the walk.
+	BackupDir string
+
+	// Cache is the path of a cache file recording the outcome of processing
+	// each file along with a hash of its contents, so that the next runs
+	// with the same options skip the files that didn't change since.
+	Cache string
+
+	// DryRun performs no writes, but writes a unified diff of the changes
+	// that would be made to each file to Diff.
+	DryRun bool
+	// Diff receives the diffs of a dry run, sorted by file path, once all
+	// files are processed. It defaults to the standard output.
+	Diff io.Writer
+
+	// Logger logs errors and, depending on LogLevel, the files that are
+	// modified or skipped. It defaults to the standard logger.
+	Logger *log.Logger
+	// Verbose logs the name of the files that are modified or skipped. It
+	// is the same as LogLevel LogDebug.
+	Verbose bool
+	// LogLevel selects the lines logged besides errors. It defaults to
+	// LogInfo.
+	LogLevel LogLevel
+	// LogStyle, if set, formats each line logged about a file given the
+	// status of the file, for example to color it on a terminal.
+	LogStyle func(status Status, line string) string
+}
+
+// DefaultWorkers is the default number of files processed concurrently.
+const DefaultWorkers = 64
+
+// DefaultQueueSize is the default number of files that may wait for a worker.
+const DefaultQueueSize = 1000
+
+// HiddenPolicy defines how hidden files and directories, whose names start
+// with a dot, are handled below the roots of a run.
+type HiddenPolicy string
+
+const (
+	HiddenSkip    HiddenPolicy = ""        // skip hidden files and directories
+	HiddenInclude HiddenPolicy = "include" // process them like any other
+)
+
+// ErrMissingLicense is the error of files missing a license header in check
+// only mode.
+var ErrMissingLicense = errors.New("missing license header")
+
+// ErrFileTimeout is the error of files not processed within the FileTimeout of
+// the run.
+var ErrFileTimeout = errors.New("timed out processing file")
+
+// Run processes the files of opts.Roots, adding missing license headers or
+// only checking for their presence, according to opts.
+//
+// It returns a report of the outcome for each file, and the first error
+// encountered processing a file, if any. Such errors don't stop processing
+// the other files. An error is returned without a report if the run could
+// not start, for example because of an invalid option, or if ctx is done.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	r, err := newRunner(opts)
+	if err != nil {
+		return nil, err
+	}
+	return r.run(ctx)
+}
+
+// runner holds the state of a run.
+type runner struct {
+	opts Options
+	log  *log.Logger
+	tmpl *template.Template
+	// footers maps file extensions, or "*", to footer templates.
+	footers map[string]*template.Template
+	// replaceTmpl is the template of the license being replaced, if any.
+	replaceTmpl *template.Template
+	shortTmpl   *template.Template
+	data        LicenseData
+	// licenses lists the licenses assigned to subtrees, in order.
+	licenses []assignedLicense
+	// anchors lists the lines after which headers are added, in order.
+	anchors []insertAnchor
+	// styles maps extensions to the comment styles assigned to them.
+	styles  map[string]*commentStyle
+	ignore  []string
+	keep    []string
+	markers [][]byte
+
+	headers headerCache
+
+	// resultMu serializes the calls of the OnResult function of the run.
+	resultMu sync.Mutex
+
+	mu         sync.Mutex
+	results    []Result
+	writeTotal time.Duration
+	writes     map[string]writeSpan // write spans of the files being processed
+	queue      QueueStats
+	depthTotal int               // sum of the queue depths, see enqueue
+	walkDone   bool              // whether the walk is over, see progress
+	failed     int               // number of files with StatusError
+	diffs      map[string]string // diffs of a dry run, by file path
+	pending    map[string][]byte // contents of the files updated in a dry run
+	output     map[string]bool   // files being processed written to outputDir
+	snapshot   *snapshot
+	backups    *backups
+	outputDir  string // absolute path of OutputDir, if set
+	cache      *cache
+	// sources maps paths to the unsaved contents of files, see CheckBuffer.
+	sources map[string][]byte
+	// sniffed maps paths to the comment styles told from their contents, see
+	// sniffStyle. It is guarded by mu.
+	sniffed map[string]*commentStyle
+	// claims holds the files of a run with a FileTimeout that started being
+	// written, true, or that timed out, false. It is guarded by mu.
+	claims map[string]bool
+}
+
+func newRunner(opts Options) (*runner, error) {
+	if opts.Remove && (opts.CheckOnly || opts.HolderRules != nil || opts.FixDuplicates) {
+		return nil, errors.New("removing license headers can't be combined with checking, rewriting or fixing them")
+	}
+	if (opts.GitStaged && opts.GitTracked) || (opts.GitSince != "" && (opts.GitStaged || opts.GitTracked)) {
+		return nil, errors.New("processing staged, tracked or changed files can't be combined")
+	}
+	if opts.FixDuplicates && (opts.CheckOnly || opts.HolderRules != nil) {
+		return nil, errors.New("fixing duplicate license headers can't be combined with checking or rewriting them")
+	}
+	r := &runner{
+		opts:    opts,
+		log:     opts.Logger,
+		ignore:  append([]string(nil), opts.Ignore...),
+		markers: licenseMarkers,
+		diffs:   make(map[string]string),
+		pending: make(map[string][]byte),
+		output:  make(map[string]bool),
+	}
+	if r.log == nil {
+		r.log = log.New(os.Stderr, "", log.LstdFlags)
+	}
+	if opts.OutputDir != "" && !opts.DryRun && !opts.CheckOnly {
+		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+			return nil, err
+		}
+		var err error
+		if r.outputDir, err = filepath.Abs(opts.OutputDir); err != nil {
+			return nil, err
+		}
+	}
+	// files written elsewhere need neither snapshots nor backups
+	writes := !opts.DryRun && !opts.CheckOnly && opts.OutputDir == ""
+	if opts.Snapshot != "" && writes {
+		var err error
+		if r.snapshot, err = newSnapshot(opts.Snapshot); err != nil {
+			return nil, err
+		}
+	}
+	if (opts.Backup || opts.BackupDir != "") && writes {
+		var err error
+		if r.backups, err = newBackups(opts.BackupDir); err != nil {
+			return nil, err
+		}
+	}
+
+	// expand presets into their ignore and keep patterns
+	for _, name := range opts.Presets {
+		p, err := lookupPreset(name)
+		if err != nil {
+			return nil, err
+		}
+		r.ignore = append(r.ignore, p.ignore...)
+		r.keep = append(r.keep, p.keep...)
+	}
+	// verify that all ignore and include patterns are valid
+	for _, p := range r.ignore {
+		if !doublestar.ValidatePattern(p) {
+			return nil, fmt.Errorf("Ignore pattern %q is not valid", p)
+		}
+	}
+	for _, p := range opts.Include {
+		if !doublestar.ValidatePattern(p) {
+			return nil, fmt.Errorf("Include pattern %q is not valid", p)
+		}
+	}
+	for _, p := range opts.IncludeHidden {
+		if !doublestar.ValidatePattern(p) {
+			return nil, fmt.Errorf("IncludeHidden pattern %q is not valid", p)
+		}
+	}
+	if opts.Hidden != HiddenSkip && opts.Hidden != HiddenInclude {
+		return nil, fmt.Errorf("unknown hidden files policy %q", opts.Hidden)
+	}
+
+	for _, m := range opts.Markers {
+		r.markers = append(r.markers, []byte(strings.ToLower(m)))
+	}
+
+	// map legacy license values
+	license := opts.License
+	if t, ok := legacyLicenseTypes[license]; ok {
+		license = t
+	}
+	r.data = LicenseData{
+		Year:   opts.Year,
+		Holder: opts.Holder,
+		SPDXID: license,
+
+		FileContributors: opts.FileContributors,
+		FileType:         opts.FileType,
+		Notice:           opts.Notice,
+	}
+
+	var err error
+	if r.styles, err = parseExtStyles(opts.ExtStyles); err != nil {
+		return nil, err
+	}
+	if err := parseCommentStyles(r.styles, opts.CommentStyles); err != nil {
+		return nil, err
+	}
+	var tpl string
+	if opts.ReferenceFile != "" {
+		if opts.TemplateFile != "" {
+			return nil, errors.New("a license template file and a reference file can't be combined")
+		}
+		tpl, err = r.referenceTemplate(opts.ReferenceFile)
+	} else {
+		tpl, err = fetchTemplate(license, opts.TemplateFile, opts.SPDX)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tpl = withNotice(tpl, opts.Notice)
+	if r.tmpl, err = template.New("").Parse(tpl); err != nil {
+		return nil, err
+	}
+	if opts.Cache != "" {
+		if r.cache, err = loadCache(opts.Cache, r.cacheConfig(tpl)); err != nil {
+			return nil, err
+		}
+	}
+	r.shortTmpl = template.Must(template.New("").Parse(withNotice(tmplShort, opts.Notice)))
+	if r.licenses, err = parseLicenseRules(opts.Licenses, opts.SPDX, r.data); err != nil {
+		return nil, err
+	}
+
+	if r.anchors, err = parseInsertRules(opts.InsertAfter); err != nil {
+		return nil, err
+	}
+	if r.footers, err = parseFooters(opts.Footers); err != nil {
+		return nil, err
+	}
+	if opts.Replace != "" {
+		if t, ok := legacyLicenseTypes[opts.Replace]; ok {
+			r.opts.Replace = t
+		}
+		tpl, ok := licenseTemplate[r.opts.Replace]
+		if !ok {
+			return nil, fmt.Errorf("unknown license to replace: %q", opts.Replace)
+		}
+		r.replaceTmpl = template.Must(template.New("").Parse(tpl))
+	}
+	return r, nil
+}
+
+func (r *runner) run(ctx context.Context) (*Report, error) {
+	report := &Report{Start: time.Now()}
+	roots := r.opts.Roots
+	if r.opts.GitStaged {
+		var err error
+		if roots, err = gitStagedFiles(r.opts.GitAddedOnly, roots); err != nil {
+			return nil, err
+		}
+	} else if r.opts.GitTracked {
+		var err error
+		if roots, err = gitTrackedFiles(roots); err != nil {
+			return nil, err
+		}
+	} else if r.opts.GitSince != "" {
+		var err error
+		if roots, err = gitChangedFiles(r.opts.GitSince, roots); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if roots, err = r.expandGlobs(roots); err != nil {
+			return nil, err
+		}
+	}
+
+	r.queue.Workers = r.opts.Workers
+	if r.queue.Workers <= 0 {
+		r.queue.Workers = DefaultWorkers
+	}
+	r.queue.Capacity = r.opts.QueueSize
+	if r.queue.Capacity <= 0 {
+		r.queue.Capacity = DefaultQueueSize
+	}
+	stopProgress := r.startProgress(report.Start)
+	ch := make(chan *file, r.queue.Capacity)
+	done := make(chan error)
+	go func() {
+		var wg errgroup.Group
+		for i := 0; i < r.queue.Workers; i++ {
+			wg.Go(func() error {
+				var first error
+				for f := range ch {
+					if err := r.processFile(f); err != nil && first == nil {
+						first = err
+					}
+				}
+				return first
+			})
+		}
+		done <- wg.Wait()
+	}()
+
+	var walkErr error
+	st := newWalkState(r.opts.WalkWorkers)
+	for _, d := range uniqueRoots(roots) {
+		if walkErr = r.walk(ctx, ch, d, st); walkErr != nil {
+			break
+		}
+	}
+	if err := st.wait(); walkErr == nil {
+		walkErr = err
+	}
+	close(ch)
+	report.WalkEnd = time.Now()
+	r.mu.Lock()
+	r.walkDone = true
+	r.mu.Unlock()
+	err := <-done
+	stopProgress()
+	report.End = time.Now()
+	if r.snapshot != nil {
+		// record the files already modified, even if the walk failed
+		if werr := r.snapshot.writeManifest(); err == nil {
+			err = werr
+		}
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	if r.cache != nil {
+		if werr := r.cache.write(); err == nil {
+			err = werr
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.Slice(r.results, func(i, j int) bool { return r.results[i].Path < r.results[j].Path })
+	report.Results = r.results
+	report.WriteDuration = r.writeTotal
+	report.Queue = r.queue
+	if r.queue.Files > 0 {
+		report.Queue.MeanDepth = float64(r.depthTotal) / float64(r.queue.Files)
+	}
+	if r.opts.DryRun {
+		if werr := r.writeDiffs(); err == nil {
+			err = werr
+		}
+	}
+	return report, err
+}
+
+type file struct {
+	path string
+	mode os.FileMode
+	log  *fileLog
+	// license and holder are found in the license header, in check only mode.
+	license, holder string
+}
+
+// expandGlobs returns roots with the doublestar patterns among them, such as
+// "src/**/*.go", replaced with the paths they match. Roots naming existing
+// files are kept as is, even if they contain pattern characters.
+func (r *runner) expandGlobs(roots []string) ([]string, error) {
+	var expanded []string
+	for _, root := range roots {
+		if _, err := os.Lstat(root); err == nil || !strings.ContainsAny(root, "*?[{") {
+			expanded = append(expanded, root)
+			continue
+		}
+		base, pattern := doublestar.SplitPattern(filepath.ToSlash(root))
+		matches, err := doublestar.Glob(os.DirFS(base), pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %v", root, err)
+		}
+		if len(matches) == 0 {
+			r.log.Printf("%s: no files match the pattern", root)
+		}
+		for _, m := range matches {
+			expanded = append(expanded, filepath.Join(filepath.FromSlash(base), filepath.FromSlash(m)))
+		}
+	}
+	return expanded, nil
+}
+
+// isHidden reports whether name is the name of a hidden file or directory.
+func isHidden(name string) bool {
+	return len(name) > 1 && name[0] == '.' && name != ".."
+}
+
+// hasHiddenElem reports whether one of the elements of path below root is
+// hidden.
+func hasHiddenElem(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		if isHidden(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathDepth returns the number of path elements of path below root.
+func pathDepth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// dirKey returns a key identifying the directory at path, described by fi,
+// regardless of the symbolic links leading to it.
+func dirKey(path string, fi os.FileInfo) string {
+	if id, ok := statID(fi); ok {
+		return fmt.Sprintf("%d:%d", id.dev, id.ino)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return canonicalPath(resolved)
+	}
+	return canonicalPath(path)
+}
+
+// uniqueRoots returns roots without the roots naming the same file or
+// directory as a previous one, such as "src" and "./src/".
+func uniqueRoots(roots []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, root := range roots {
+		key := canonicalPath(root)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, root)
+		}
+	}
+	return unique
+}
+
+// canonicalPath returns the absolute form of path, or its cleaned form if it
+// can't be made absolute, so that different spellings of a path compare equal.
+func canonicalPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// fileID identifies a file regardless of the paths naming it.
+type fileID struct {
+	dev, ino uint64
+}
+
+// walkState records the files found by the walks of a run, which may run
+// concurrently.
+type walkState struct {
+	mu    sync.Mutex
+	paths map[string]bool
+	// links maps the files with several hard links to the first path found.
+	links map[fileID]string
+	// dirs records the directories walked when following symbolic links.
+	dirs map[string]bool
+
+	// sem limits the number of subtrees walked concurrently, in group.
+	sem   chan struct{}
+	group errgroup.Group
+}
+
+func newWalkState(workers int) *walkState {
+	st := &walkState{paths: make(map[string]bool), links: make(map[fileID]string), dirs: make(map[string]bool)}
+	if workers > 1 {
+		st.sem = make(chan struct{}, workers-1)
+	}
+	return st
+}
+
+// add records a file, identified by key, and returns false if it was found
+// before.
+func (st *walkState) add(key string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.paths[key] {
+		return false
+	}
+	st.paths[key] = true
+	return true
+}
+
+// addLink records the path of a file with several hard links and returns the
+// first path found if the file was found before.
+func (st *walkState) addLink(id fileID, path string) (first string, ok bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if first, ok := st.links[id]; ok {
+		return first, true
+	}
+	st.links[id] = path
+	return "", false
+}
+
+// addDir records a directory, identified by key, and returns false if it was
+// walked before.
+func (st *walkState) addDir(key string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.dirs[key] {
+		return false
+	}
+	st.dirs[key] = true
+	return true
+}
+
+// spawn runs walk in the background if a worker is available, and reports
+// whether it did.
+func (st *walkState) spawn(walk func() error) bool {
+	select {
+	case st.sem <- struct{}{}:
+		st.group.Go(func() error {
+			defer func() { <-st.sem }()
+			return walk()
+		})
+		return true
+	default:
+		return false
+	}
+}
+
+// wait waits for the subtrees walked in the background.
+func (st *walkState) wait() error {
+	return st.group.Wait()
+}
+
+// walk sends the files found under start to ch. Files recorded in st, found
+// under an overlapping root walked before or through another hard link, are
+// skipped: processing a file twice concurrently could add its license header
+// twice.
+func (r *runner) walk(ctx context.Context, ch chan<- *file, start string, st *walkState) error {
+	if r.opts.FollowSymlinks {
+		if fi, err := os.Stat(start); err == nil && fi.IsDir() {
+			st.addDir(dirKey(start, fi))
+		}
+	}
+	return r.walkTree(ctx, ch, start, start, start, st)
+}
+
+// walkTree walks the tree at dir, whose files are named below name instead:
+// they differ when following a symbolic link name to dir.
+func (r *runner) walkTree(ctx context.Context, ch chan<- *file, start, name, dir string, st *walkState) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		realPath := path
+		if dir != name {
+			rel, _ := filepath.Rel(dir, path)
+			path = filepath.Join(name, rel)
+		}
+		if err != nil {
+			r.log.Printf("%s error: %v", path, err)
+			return nil
+		}
+		if d.IsDir() {
+			if r.snapshot != nil && canonicalPath(path) == r.snapshot.dir {
+				return filepath.SkipDir
+			}
+			if r.backups != nil && r.backups.dir != "" && canonicalPath(path) == r.backups.dir {
+				return filepath.SkipDir
+			}
+			if r.outputDir != "" && canonicalPath(path) == r.outputDir {
+				return filepath.SkipDir
+			}
+			if r.opts.MaxDepth > 0 && pathDepth(start, path) >= r.opts.MaxDepth {
+				return filepath.SkipDir
+			}
+			if path != start && !r.opts.WalkVCS && isVCSDir(d.Name()) {
+				r.logf(r.log, LogDebug, "skipping: %s: version control metadata", path)
+				return filepath.SkipDir
+			}
+			// hidden directories are only walked if some of their files could
+			// be processed regardless
+			if path != start && r.opts.Hidden == HiddenSkip && isHidden(d.Name()) && len(r.opts.IncludeHidden) == 0 && len(r.keep) == 0 {
+				r.logf(r.log, LogDebug, "skipping: %s: hidden", path)
+				return filepath.SkipDir
+			}
+			// roots are walked even if they are ignored by default
+			if path != start && !r.opts.NoDefaultIgnores && isDefaultIgnoredDir(d.Name()) {
+				r.logf(r.log, LogDebug, "skipping: %s: ignored by default", path)
+				return filepath.SkipDir
+			}
+			if realPath == dir {
+				return nil
+			}
+			if r.opts.FollowSymlinks {
+				fi, err := d.Info()
+				if err != nil {
+					r.log.Printf("%s error: %v", path, err)
+					return filepath.SkipDir
+				}
+				if !st.addDir(dirKey(realPath, fi)) {
+					r.logf(r.log, LogDebug, "skipping: %s: directory already walked, symbolic link loop?", path)
+					return filepath.SkipDir
+				}
+			}
+			if st.spawn(func() error { return r.walkTree(ctx, ch, start, path, realPath, st) }) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// reading FIFOs, sockets or devices could block forever
+		key := canonicalPath(realPath)
+		var fi os.FileInfo
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Stat(realPath)
+			if err == nil && target.IsDir() {
+				if !r.opts.FollowSymlinks {
+					r.logf(r.log, LogDebug, "skipping: %s: symbolic link to a directory", path)
+					return nil
+				}
+				resolved, err := filepath.EvalSymlinks(realPath)
+				if err != nil {
+					r.log.Printf("%s error: %v", path, err)
+					return nil
+				}
+				if !st.addDir(dirKey(resolved, target)) {
+					r.logf(r.log, LogDebug, "skipping: %s: directory already walked, symbolic link loop?", path)
+					return nil
+				}
+				return r.walkTree(ctx, ch, start, path, resolved, st)
+			}
+			if err == nil {
+				fi = target
+				// links to the same file are processed once
+				if resolved, err := filepath.EvalSymlinks(realPath); err == nil {
+					key = canonicalPath(resolved)
+				}
+			}
+		}
+		if fi == nil && d.Type().IsRegular() {
+			if fi, err = d.Info(); err != nil {
+				r.log.Printf("%s error: %v", path, err)
+				return nil
+			}
+		}
+		if fi == nil || !fi.Mode().IsRegular() {
+			r.logf(r.log, LogDebug, "skipping: %s: not a regular file", path)
+			return nil
+		}
+		if r.opts.Hidden == HiddenSkip && hasHiddenElem(start, path) && !fileMatches(path, r.opts.IncludeHidden) && !fileMatches(path, r.keep) {
+			r.logf(r.log, LogDebug, "skipping: %s: hidden", path)
+			return nil
+		}
+		if !isIncluded(path, r.opts.Include) || isIgnored(path, r.ignore, r.keep) || !hasExtension(path, r.opts.OnlyExtensions) {
+			r.logf(r.log, LogDebug, "skipping: %s", path)
+			return nil
+		}
+		if r.opts.MaxSize > 0 && fi.Size() > r.opts.MaxSize {
+			r.logf(r.log, LogInfo, "skipping: %s: %d bytes, larger than the maximum size of %d", path, fi.Size(), r.opts.MaxSize)
+			return nil
+		}
+		if !st.add(key) {
+			return nil
+		}
+		if id, ok := linkID(fi); ok {
+			if first, ok := st.addLink(id, path); ok {
+				r.logf(r.log, LogDebug, "skipping: %s: hard link to %s", path, first)
+				return nil
+			}
+		}
+		return r.enqueue(ctx, ch, &file{path: path, mode: fi.Mode(), log: &fileLog{}})
+	})
+}
+
+// enqueue sends f to the workers through ch, waiting for room in the queue if
+// it is full, and records the queue statistics.
+func (r *runner) enqueue(ctx context.Context, ch chan<- *file, f *file) error {
+	var blocked time.Duration
+	select {
+	case ch <- f:
+	default:
+		start := time.Now()
+		select {
+		case ch <- f:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		blocked = time.Since(start)
+	}
+	depth := len(ch)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queue.Files++
+	r.queue.Blocked += blocked
+	r.depthTotal += depth
+	if depth > r.queue.MaxDepth {
+		r.queue.MaxDepth = depth
+	}
+	return nil
+}
+
+// processFile checks or updates the license header of f, depending on the
+// mode of operation, and records the outcome. It returns the error that
+// occurred, unless downgraded to a warning.
+func (r *runner) processFile(f *file) error {
+	start := time.Now()
+	var status Status
+	var err error
+	var hash string
+	if r.cache != nil {
+		var cached *cacheEntry
+		if hash, cached, err = r.cache.lookup(f.path); cached != nil {
+			status, err = cached.Status, statusError(cached.Status)
+			f.license, f.holder = cached.License, cached.Holder
+			f.log.lines = append(f.log.lines, cached.Log...)
+		}
+	}
+	if status == "" && err == nil {
+		status, err = r.updateFileTimeout(f)
+		if hash != "" {
+			r.cache.store(f.path, hash, cacheEntry{Status: status, License: f.license, Holder: f.holder, Log: f.log.lines})
+		}
+	}
+	if err != nil && err != ErrMissingLicense && err != ErrMissingFooter && err != ErrWrongLicense && err != ErrForbiddenLicense {
+		if err = r.reportError(f.log, f.path, err); err == nil {
+			status = StatusWarning
+		} else {
+			status = StatusError
+		}
+	}
+	if r.outputDir != "" {
+		if oerr := r.writeOutput(f.path, status); oerr != nil {
+			if err = r.reportError(f.log, f.path, oerr); err == nil {
+				status = StatusWarning
+			} else {
+				status = StatusError
+			}
+		}
+	}
+	if status == StatusModified {
+		r.logf(f.log, LogDebug, "%s modified", f.path)
+	} else if status == StatusOK && r.opts.CheckOnly {
+		r.logf(f.log, LogDebug, "%s ok", f.path)
+	}
+	if r.opts.LogStyle != nil {
+		f.log.style(func(line string) string { return r.opts.LogStyle(status, line) })
+	}
+	f.log.flush(r.log)
+	end := time.Now()
+	r.mu.Lock()
+	w := r.writes[f.path]
+	delete(r.writes, f.path)
+	r.mu.Unlock()
+	res := Result{Path: f.path, Status: status, Err: err, Start: start, Duration: end.Sub(start), WriteStart: w.start, WriteEnd: w.end, License: f.license, Holder: f.holder}
+	if r.opts.OnResult != nil {
+		r.resultMu.Lock()
+		r.opts.OnResult(res)
+		r.resultMu.Unlock()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, res)
+	if status == StatusError {
+		r.failed++
+		if r.failed <= r.opts.TolerateErrors {
+			return nil
+		}
+	}
+	return err
+}
+
+// updateFileTimeout calls updateFile, failing f with ErrFileTimeout if it
+// takes longer than the FileTimeout of the run. A file blocked in a system
+// call can't be interrupted: the call is left running in the background, and
+// its log lines are discarded. It is then kept from writing the file, see
+// claimWrite, and a file that started being written when the timeout expires
+// is waited for instead, so that files failed with ErrFileTimeout are never
+// modified.
+func (r *runner) updateFileTimeout(f *file) (Status, error) {
+	if r.opts.FileTimeout <= 0 {
+		return r.updateFile(f)
+	}
+	type result struct {
+		status Status
+		err    error
+	}
+	flog := &fileLog{}
+	inner := &file{path: f.path, mode: f.mode, log: flog}
+	done := make(chan result, 1)
+	go func() {
+		status, err := r.updateFile(inner)
+		done <- result{status, err}
+	}()
+	timer := time.NewTimer(r.opts.FileTimeout)
+	defer timer.Stop()
+	var res result
+	select {
+	case res = <-done:
+	case <-timer.C:
+		r.mu.Lock()
+		writing := r.claims[f.path]
+		if !writing {
+			if r.claims == nil {
+				r.claims = make(map[string]bool)
+			}
+			r.claims[f.path] = false
+		}
+		r.mu.Unlock()
+		if !writing {
+			return StatusError, ErrFileTimeout
+		}
+		res = <-done
+	}
+	r.mu.Lock()
+	delete(r.claims, f.path)
+	r.mu.Unlock()
+	f.log.lines = append(f.log.lines, flog.lines...)
+	f.license, f.holder = inner.license, inner.holder
+	return res.status, res.err
+}
+
+// claimWrite reports whether the file at path may be written: in runs with
+// a FileTimeout, it fails with ErrFileTimeout once the file timed out, and
+// otherwise marks the file as being written, so that it no longer times out.
+func (r *runner) claimWrite(path string) error {
+	if r.opts.FileTimeout <= 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if writing, ok := r.claims[path]; ok && !writing {
+		return ErrFileTimeout
+	}
+	if r.claims == nil {
+		r.claims = make(map[string]bool)
+	}
+	r.claims[path] = true
+	return nil
+}
+
+// updateFile checks or updates the license header of f, depending on the
+// mode of operation, and returns the resulting status.
+func (r *runner) updateFile(f *file) (Status, error) {
+	if r.opts.CheckOnly {
+		// Check if file extension is known
+		if r.style(f.path) == nil {
+			return StatusSkipped, nil
+		}
+		_, data, _ := r.license(f.path)
+		// Check if file has a license
+		b, release, err := r.readPooled(f.path)
+		if err != nil {
+			return StatusError, err
+		}
+		defer release()
+		if status, skip := r.skipContent(f, b); skip {
+			return status, nil
+		}
+		// If generated, we count it as if it has a license.
+		if !r.hasLicense(b) && !isGenerated(b) {
+			return StatusMissing, ErrMissingLicense
+		}
+		f.license, f.holder = DetectHeaderLicense(b), copyrightHolder(headWindow(b, 1000))
+		if f.license != "" && isForbidden(f.license, r.opts.Forbid) {
+			f.log.Printf("%s: license header of forbidden license %s", f.path, f.license)
+			return StatusForbidden, ErrForbiddenLicense
+		}
+		if ok, err := r.hasAssignedLicense(f.path, b); err != nil {
+			return StatusError, err
+		} else if !ok {
+			f.log.Printf("%s: license header isn't the one of %s", f.path, data.SPDXID)
+			return StatusWrongLicense, ErrWrongLicense
+		}
+		footer, err := r.footer(f.path)
+		if err != nil {
+			return StatusError, err
+		}
+		if footer != nil && !isGenerated(b) && !hasFooter(b, footer) {
+			f.log.Printf("%s: missing license footer", f.path)
+			return StatusMissingFooter, ErrMissingFooter
+		}
+		if _, _, _, ok := duplicateLicense(r.style(f.path), f.path, b, r.markers); ok {
+			f.log.Printf("%s: duplicate license header", f.path)
+			return StatusDuplicate, nil
+		}
+		return StatusOK, nil
+	}
+
+	if r.style(f.path) == nil {
+		return StatusSkipped, nil
+	}
+	head, err := r.readHead(f.path)
+	if err != nil {
+		return StatusError, err
+	}
+	if status, skip := r.skipContent(f, head); skip {
+		return status, nil
+	}
+	var modified bool
+	if r.opts.Remove {
+		modified, err = r.removeLicense(f.path, f.mode)
+	} else if r.opts.FixDuplicates {
+		modified, err = r.fixDuplicates(f.log, f.path, f.mode)
+	} else if r.opts.HolderRules != nil {
+		modified, err = r.rewriteHolder(f.path, f.mode, r.opts.HolderRules)
+	} else {
+		if r.opts.Replace != "" {
+			modified, err = r.replaceLicense(f.path, f.mode)
+		}
+		if err == nil && !modified {
+			modified, err = r.addLicense(f.path, f.mode)
+		}
+		if err == nil && !modified && r.opts.NormalizeYears != YearsKeep {
+			modified, err = r.normalizeYears(f.path, f.mode, r.opts.NormalizeYears, time.Now().Year())
+		}
+		if err == nil {
+			var added bool
+			added, err = r.addFooter(f.path, f.mode)
+			modified = modified || added
+		}
+	}
+	if err != nil {
+		return StatusError, err
+	}
+	if modified {
+		return StatusModified, nil
+	}
+	return StatusOK, nil
+}
+
+// readFile returns the contents of the file at path, including the updates
+// made to it so far in a dry run or in the output directory.
+func (r *runner) readFile(path string) ([]byte, error) {
+	r.mu.Lock()
+	b, ok := r.pending[path]
+	output := r.output[path]
+	r.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+	if output {
+		return ioutil.ReadFile(mirrorPath(r.outputDir, path))
+	}
+	return r.source(path)
+}
+
+// skipContent reports whether f is skipped because of its contents b, or
+// their start: binary files and, unless requested, minified ones. It returns
+// the status of skipped files.
+func (r *runner) skipContent(f *file, b []byte) (Status, bool) {
+	if isBinary(b) {
+		r.logf(f.log, LogDebug, "skipping: %s: binary", f.path)
+		return StatusBinary, true
+	}
+	if !r.opts.Minified && isMinified(f.path, b) {
+		r.logf(f.log, LogDebug, "skipping: %s: minified", f.path)
+		return StatusMinified, true
+	}
+	return "", false
+}
+
+// readHead returns the start of the file at path, enough to tell whether
+// skipContent skips it: its first binaryBlock bytes, and those of the rune
+// straddling their end, if any.
+func (r *runner) readHead(path string) ([]byte, error) {
+	if b, ok := r.sources[path]; ok {
+		return b, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b := make([]byte, binaryBlock+utf8.UTFMax)
+	n, err := io.ReadFull(f, b)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return b[:n], nil
+}
+
+// readPool holds the buffers of the files read in check only mode, which
+// don't outlive their checks, to spare an allocation per file.
+var readPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// maxPooledRead is the capacity above which read buffers aren't pooled, so
+// that a few huge files don't keep memory in use.
+const maxPooledRead = 1 << 20
+
+// readPooled returns the contents of the file at path, like readFile, in a
+// pooled buffer. The contents must not be used after calling release.
+func (r *runner) readPooled(path string) (b []byte, release func(), err error) {
+	if b, ok := r.sources[path]; ok {
+		return b, func() {}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	buf := readPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	release = func() {
+		if buf.Cap() <= maxPooledRead {
+			readPool.Put(buf)
+		}
+	}
+	if _, err := buf.ReadFrom(f); err != nil {
+		release()
+		return nil, nil, err
+	}
+	return buf.Bytes(), release, nil
+}
+
+// source returns the contents of the file at path before the run.
+func (r *runner) source(path string) ([]byte, error) {
+	if b, ok := r.sources[path]; ok {
+		return b, nil
+	}
+	return ioutil.ReadFile(path)
+}
+
+// writeDiffs writes the diffs of a dry run to opts.Diff, sorted by path.
+func (r *runner) writeDiffs() error {
+	w := r.opts.Diff
+	if w == nil {
+		w = os.Stdout
+	}
+	paths := make([]string, 0, len(r.diffs))
+	for path := range r.diffs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if _, err := io.WriteString(w, r.diffs[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileMatches determines if path matches one of the provided file patterns.
+// Patterns are assumed to be valid.
+func fileMatches(path string, patterns []string) bool {
+	for _, p := range patterns {
+		// ignore error, since we assume patterns are valid
+		if match, _ := doublestar.Match(p, path); match {
+			return true
+		}
+	}
+	return false
+}
+
+// isIgnored reports whether path matches one of the ignore patterns and none
+// of the keep patterns, which take precedence.
+func isIgnored(path string, ignore, keep []string) bool {
+	return fileMatches(path, ignore) && !fileMatches(path, keep)
+}
+
+// VCSDirs lists the names of the version control metadata directories, which
+// are never walked unless requested.
+var VCSDirs = []string{".git", ".hg", ".svn", ".bzr"}
+
+// isVCSDir reports whether name is one of VCSDirs.
+func isVCSDir(name string) bool {
+	for _, d := range VCSDirs {
+		if name == d {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultIgnoredDirs lists the names of the directories skipped by default:
+// dependencies, vendored code and build outputs, which hold files that aren't
+// the project's own.
+var DefaultIgnoredDirs = []string{
+	"node_modules", "bower_components", "jspm_packages", "vendor",
+	"third_party", "3rdparty", "Pods", "Carthage",
+	"dist", "__pycache__", ".venv", ".tox",
+}
+
+// isDefaultIgnoredDir reports whether name is one of DefaultIgnoredDirs.
+func isDefaultIgnoredDir(name string) bool {
+	for _, d := range DefaultIgnoredDirs {
+		if name == d {
+			return true
+		}
+	}
+	return false
+}
+
+// isIncluded reports whether path matches one of the include patterns, or
+// true if there are none.
+func isIncluded(path string, include []string) bool {
+	return len(include) == 0 || fileMatches(path, include)
+}
+
+// hasExtension reports whether the file at path has one of exts, or true if
+// exts is empty.
+func hasExtension(path string, exts []string) bool {
+	if len(exts) == 0 {
+		return true
+	}
+	ext := extKey(path)
+	for _, e := range exts {
+		if strings.TrimPrefix(strings.ToLower(e), ".") == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// addLicense add a license to the file if missing.
+//
+// It returns true if the file was updated.
+func (r *runner) addLicense(path string, fmode os.FileMode) (bool, error) {
+	if fi, err := os.Stat(path); err == nil && r.canStream(path, fi.Size()) {
+		return r.streamLicense(path, fmode)
+	}
+	b, err := r.readFile(path)
+	if err != nil {
+		return false, err
+	}
+	if r.hasLicense(b) || isGenerated(b) {
+		return false, err
+	}
+
+	lic, err := r.licenseHeader(path)
+	if err != nil || lic == nil {
+		return false, err
+	}
+	line := r.leadingLines(path, b)
+	b = append(prependLines(line, lic), b[len(line):]...)
+	return true, r.writeFile(path, b, fmode)
+}
+
+// licenseHeader returns the license header of the file at path, or nil if
+// its comment style is unknown.
+func (r *runner) licenseHeader(path string) ([]byte, error) {
+	tmpl, data, _ := r.license(path)
+	if r.opts.GitYears {
+		years, err := gitYears(path)
+		if err != nil {
+			return nil, err
+		}
+		if years != "" {
+			data.Year = years
+		}
+	}
+	return r.headers.header(r.style(path), tmpl, data)
+}
+
+// leadingLines returns the lines at the start of b, the contents of the file
+// at path, that must stay above its license header: a hashbang line, in
+// notebooks, their Jupytext metadata header and, in Markdown
+// documents, their front matter.
+func leadingLines(path string, b []byte) []byte {
+	line := hashBang(b)
+	if isPercentScript(path) {
+		line = append(line, percentPreamble(b[len(line):])...)
+	}
+	if isMarkdown(path) {
+		line = append(line, frontMatter(b[len(line):])...)
+	}
+	return line
+}
+
+// prependLines returns the license header lic preceded by the leading lines
+// of a file, terminated by a newline.
+func prependLines(line, lic []byte) []byte {
+	if len(line) == 0 {
+		return lic
+	}
+	line = append([]byte(nil), line...)
+	if line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+	return append(line, lic...)
+}
+
+// writeFile writes b, the updated contents of the file at path, with
+// replaceFile, or to the output directory. In a dry run, it records the diff
+// of the changes instead.
+func (r *runner) writeFile(path string, b []byte, fmode os.FileMode) error {
+	if r.opts.VerifyGo && isGoFile(path) {
+		old, err := r.readFile(path)
+		if err != nil {
+			return err
+		}
+		if err := verifyGo(path, old, b); err != nil {
+			return err
+		}
+	}
+	if err := r.claimWrite(path); err != nil {
+		return err
+	}
+	if r.opts.DryRun {
+		old, err := r.source(path)
+		if err != nil {
+			return err
+		}
+		d := unifiedDiff(path, path, old, b)
+		r.mu.Lock()
+		r.diffs[path] = d
+		r.pending[path] = b
+		r.mu.Unlock()
+		return nil
+	}
+	if r.outputDir != "" {
+		dst := mirrorPath(r.outputDir, path)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(dst, b, fmode.Perm()); err != nil {
+			return err
+		}
+		r.mu.Lock()
+		r.output[path] = true
+		r.mu.Unlock()
+		return nil
+	}
+	if r.snapshot != nil || r.backups != nil {
+		old, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if r.snapshot != nil {
+			if err := r.snapshot.save(path, fmode, old, b); err != nil {
+				return err
+			}
+		}
+		if r.backups != nil {
+			if err := r.backups.save(path, fmode, old); err != nil {
+				return err
+			}
+		}
+	}
+	start := time.Now()
+	err := replaceFile(path, fmode, func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+	r.wrote(path, start)
+	return err
+}
+
+// writeSpan is the span of time in which a file is written: from the start
+// of its first write to the end of its last one.
+type writeSpan struct {
+	start, end time.Time
+}
+
+// wrote records that the file at path was written from start until now, for
+// Report.WriteDuration and the write span of the Result of the file.
+func (r *runner) wrote(path string, start time.Time) {
+	end := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writeTotal += end.Sub(start)
+	if r.writes == nil {
+		r.writes = make(map[string]writeSpan)
+	}
+	w, ok := r.writes[path]
+	if !ok {
+		w.start = start
+	}
+	w.end = end
+	r.writes[path] = w
+}
+
+// hasLicense reports whether b contains one of the license markers of the
+// run, see hasLicense.
+func (r *runner) hasLicense(b []byte) bool {
+	return containsMarker(b, r.markers)
+}
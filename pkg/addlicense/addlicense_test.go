@@ -0,0 +1,1042 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func tempDir(t testing.TB) string {
+	dir, err := ioutil.TempDir("", "addlicense")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func createTempFile(contents string, pattern string) (*os.File, error) {
+	f, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(f.Name(), []byte(contents), 0644); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func TestAddLicense(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse("{{.Holder}}{{.Year}}{{.SPDXID}}"))
+	data := LicenseData{Holder: "H", Year: "Y", SPDXID: "S"}
+
+	tests := []struct {
+		contents     string
+		wantContents string
+		wantUpdated  bool
+	}{
+		{"", "// HYS\n\n", true},
+		{"content", "// HYS\n\ncontent", true},
+
+		// various headers that should be left intact. Many don't make
+		// sense for our temp file extension, but that doesn't matter.
+		{"#!/bin/bash\ncontent", "#!/bin/bash\n// HYS\n\ncontent", true},
+		{"<?xml version='1.0'?>\ncontent", "<?xml version='1.0'?>\n// HYS\n\ncontent", true},
+		{"<!doctype html>\ncontent", "<!doctype html>\n// HYS\n\ncontent", true},
+		{"<!DOCTYPE HTML>\ncontent", "<!DOCTYPE HTML>\n// HYS\n\ncontent", true},
+		{"# encoding: UTF-8\ncontent", "# encoding: UTF-8\n// HYS\n\ncontent", true},
+		{"# frozen_string_literal: true\ncontent", "# frozen_string_literal: true\n// HYS\n\ncontent", true},
+		{"<?php\ncontent", "<?php\n// HYS\n\ncontent", true},
+		{"# escape: `\ncontent", "# escape: `\n// HYS\n\ncontent", true},
+		{"# syntax: docker/dockerfile:1.3\ncontent", "# syntax: docker/dockerfile:1.3\n// HYS\n\ncontent", true},
+		{"@ECHO OFF\r\ncontent", "@ECHO OFF\r\n// HYS\n\ncontent", true},
+		{"Attribute VB_Name = \"Module1\"\ncontent", "Attribute VB_Name = \"Module1\"\n// HYS\n\ncontent", true},
+
+		// ensure files with existing license or generated files are
+		// skipped. No need to test all permutations of these, since
+		// there are specific tests below.
+		{"// Copyright 2000 Acme\ncontent", "// Copyright 2000 Acme\ncontent", false},
+		{"// Code generated by go generate; DO NOT EDIT.\ncontent", "// Code generated by go generate; DO NOT EDIT.\ncontent", false},
+	}
+
+	for _, tt := range tests {
+		// create temp file with contents
+		f, err := createTempFile(tt.contents, "*.go")
+		if err != nil {
+			t.Error(err)
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			t.Error(err)
+		}
+
+		// run addlicense
+		r := &runner{tmpl: tmpl, data: data, markers: licenseMarkers, log: log.New(ioutil.Discard, "", 0)}
+		updated, err := r.addLicense(f.Name(), fi.Mode())
+		if err != nil {
+			t.Error(err)
+		}
+
+		// check results
+		if updated != tt.wantUpdated {
+			t.Errorf("addLicense with contents %q returned updated: %t, want %t", tt.contents, updated, tt.wantUpdated)
+		}
+		gotContents, err := ioutil.ReadFile(f.Name())
+		if err != nil {
+			t.Error(err)
+		}
+		if got := string(gotContents); got != tt.wantContents {
+			t.Errorf("addLicense with contents %q returned contents: %q, want %q", tt.contents, got, tt.wantContents)
+		}
+
+		// if all tests passed, cleanup temp file
+		if !t.Failed() {
+			_ = os.Remove(f.Name())
+		}
+	}
+}
+
+// Test that license headers of Perl and Raku files go below the hashbang line
+// and above the pragmas, such as use strict, that start the code.
+func TestAddLicensePerl(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse("{{.Holder}}{{.Year}}"))
+	data := LicenseData{Holder: "H", Year: "Y"}
+
+	tests := []struct {
+		pattern      string
+		contents     string
+		wantContents string
+	}{
+		{"*.pl", "#!/usr/bin/perl -w\nuse strict;\n", "#!/usr/bin/perl -w\n# HY\n\nuse strict;\n"},
+		{"*.pl", "use strict;\nuse warnings;\n", "# HY\n\nuse strict;\nuse warnings;\n"},
+		{"*.pm", "package Foo;\nuse strict;\n1;\n", "# HY\n\npackage Foo;\nuse strict;\n1;\n"},
+		{"*.t", "#!perl -T\nuse Test::More;\n", "#!perl -T\n# HY\n\nuse Test::More;\n"},
+		{"*.raku", "#!/usr/bin/env raku\nuse v6;\n", "#!/usr/bin/env raku\n# HY\n\nuse v6;\n"},
+		{"*.rakumod", "unit module Foo;\n", "# HY\n\nunit module Foo;\n"},
+	}
+	for _, tt := range tests {
+		f, err := createTempFile(tt.contents, tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		fi, err := f.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &runner{tmpl: tmpl, data: data, markers: licenseMarkers, log: log.New(ioutil.Discard, "", 0)}
+		if _, err := r.addLicense(f.Name(), fi.Mode()); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != tt.wantContents {
+			t.Errorf("addLicense of %s with contents %q wrote %q, want %q", tt.pattern, tt.contents, got, tt.wantContents)
+		}
+	}
+}
+
+// Test that license headers are added using the appropriate prefix for
+// different filenames and extensions.
+func TestLicenseHeader(t *testing.T) {
+	tpl := template.Must(template.New("").Parse("{{.Holder}}{{.Year}}{{.SPDXID}}"))
+	data := LicenseData{Holder: "H", Year: "Y", SPDXID: "S"}
+
+	tests := []struct {
+		paths []string // paths passed to licenseHeader
+		want  string   // expected result of executing template
+	}{
+		{
+			[]string{"f.unknown"},
+			"",
+		},
+		{
+			[]string{"f.c", "f.h", "f.gv", "f.java", "f.scala", "f.kt", "f.kts"},
+			"/*\n * HYS\n */\n\n",
+		},
+		{
+			[]string{"f.js", "f.mjs", "f.cjs", "f.jsx", "f.tsx", "f.css", "f.scss", "f.sass", "f.ts"},
+			"/**\n * HYS\n */\n\n",
+		},
+		{
+			[]string{"f.cc", "f.cpp", "f.cs", "f.go", "f.hcl", "f.hh", "f.hpp", "f.m", "f.mm", "f.proto",
+				"f.rs", "f.swift", "f.dart", "f.groovy", "f.v", "f.sv", "f.php", "f.adoc"},
+			"// HYS\n\n",
+		},
+		{
+			[]string{"f.py", "f.sh", "f.yaml", "f.yml", "f.dockerfile", "dockerfile", "f.rb", "gemfile", "f.tcl", "f.tf", "f.tofu", "f.bzl", "f.pl", "f.pp", "build", "f.org",
+				"f.pm", "f.t", "f.raku", "f.rakumod", "f.ps1", "f.psm1", "f.psd1", "f.s", "f.S", "f.toml", "f.cfg", "f.properties",
+				"f.r", "f.R", "f.jl"},
+			"# HYS\n\n",
+		},
+		{
+			[]string{"f.el", "f.lisp"},
+			";; HYS\n\n",
+		},
+		{
+			[]string{"f.erl", "f.tex", "f.sty", "f.cls", "f.bib"},
+			"% HYS\n\n",
+		},
+		{
+			[]string{"f.ini", "F.INI", "f.asm"},
+			"; HYS\n\n",
+		},
+		{
+			[]string{"f.f", "f.F", "f.f90", "f.F90", "f.f95", "f.f03"},
+			"! HYS\n\n",
+		},
+		{
+			[]string{"f.pas", "f.dpr", "f.lpr"},
+			"{\n  HYS\n}\n\n",
+		},
+		{
+			[]string{"f.vb", "f.vbs", "f.bas"},
+			"' HYS\n\n",
+		},
+		{
+			[]string{"f.rst"},
+			".. HYS\n\n",
+		},
+		{
+			[]string{"f.hs", "f.sql", "f.sdl", "f.lua", "f.ads", "f.adb"},
+			"-- HYS\n\n",
+		},
+		{
+			[]string{"f.html", "f.xml", "f.vue", "f.wxi", "f.wxl", "f.wxs", "f.md", "f.markdown"},
+			"<!--\n HYS\n-->\n\n",
+		},
+		{
+			[]string{"f.ml", "f.mli", "f.mll", "f.mly"},
+			"(**\n   HYS\n*)\n\n",
+		},
+		{
+			[]string{"f.bat", "f.cmd", "F.BAT"},
+			"REM HYS\n\n",
+		},
+		{
+			[]string{"cmakelists.txt", "f.cmake", "f.cmake.in", "Makefile", "makefile", "GNUmakefile", "f.mk"},
+			"# HYS\n\n",
+		},
+
+		// ensure matches are case insenstive
+		{
+			[]string{"F.PY", "DoCkErFiLe"},
+			"# HYS\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, path := range tt.paths {
+			header, _ := licenseHeader(fileCommentStyle(path), tpl, data)
+			if got := string(header); got != tt.want {
+				t.Errorf("licenseHeader(%q) returned: %q, want: %q", path, got, tt.want)
+			}
+		}
+	}
+}
+
+// Test that generated files are properly recognized.
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"", false},
+		{"Generated", false},
+		{"// Code generated by go generate; DO NOT EDIT.", true},
+		{"/*\n* Code generated by go generate; DO NOT EDIT.\n*/\n", true},
+		{"DO NOT EDIT! Replaced on runs of cargo-raze", true},
+		{"# This file is maintained automatically by \"terraform init\".\n# Manual edits may be lost in future updates.\n", true},
+		{"# This file is maintained automatically by \"tofu init\".\n", true},
+	}
+
+	for _, tt := range tests {
+		b := []byte(tt.content)
+		if got := isGenerated(b); got != tt.want {
+			t.Errorf("isGenerated(%q) returned %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"", false},
+		{"package a\n", false},
+		{"// héllo wörld\n", false},
+		{"\x7fELF\x02\x01\x01\x00", true},
+		{"caf\xe9\n", true},
+		{strings.Repeat("a", binaryBlock) + "\x00", false},
+		{strings.Repeat("a", binaryBlock-1) + "é", false},
+	}
+
+	for _, tt := range tests {
+		if got := isBinary([]byte(tt.content)); got != tt.want {
+			t.Errorf("isBinary(%.40q) returned %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestBinaryFiles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"a.go":   "package a\n",
+		"b.go":   "package b\x00\x01",
+		"c.sh":   "\x89PNG\r\n\x1a\n",
+		"d.json": "\x00",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, check := range []bool{true, false} {
+		report, _ := Run(context.Background(), Options{
+			Roots:     []string{dir},
+			License:   "MIT",
+			CheckOnly: check,
+			Logger:    log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal("Run returned no report")
+		}
+		statuses := make(map[string]Status)
+		for _, res := range report.Results {
+			statuses[filepath.Base(res.Path)] = res.Status
+		}
+		want := map[string]Status{"b.go": StatusBinary, "c.sh": StatusBinary, "d.json": StatusSkipped}
+		if check {
+			want["a.go"] = StatusMissing
+		} else {
+			want["a.go"] = StatusModified
+		}
+		if !reflect.DeepEqual(statuses, want) {
+			t.Errorf("check %v: statuses are %v, want %v", check, statuses, want)
+		}
+	}
+	for _, name := range []string{"b.go", "c.sh"} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != files[name] {
+			t.Errorf("binary file %s was modified: %q", name, b)
+		}
+	}
+}
+
+func TestIsMinified(t *testing.T) {
+	long := strings.Repeat("var a=1;", 20)
+	tests := []struct {
+		path    string
+		content string
+		want    bool
+	}{
+		{"app.js", "function a() {\n  return 1;\n}\n", false},
+		{"app.js", long + "\n", true},
+		{"app.js", "/*! lib v1 */\n" + long + long, true},
+		{"app.min.js", "", true},
+		{"style-min.css", "a{}\n", true},
+		{"style.css", "a {\n  color: red;\n}\n", false},
+		{"main.go", long, false},
+		{"app.js", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := isMinified(tt.path, []byte(tt.content)); got != tt.want {
+			t.Errorf("isMinified(%s, %.40q) returned %v, want %v", tt.path, tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestMinifiedFiles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"app.js":     "function a() {\n  return 1;\n}\n",
+		"bundle.js":  strings.Repeat("var a=1;", 100) + "\n//# sourceMappingURL=bundle.js.map\n",
+		"lib.min.js": "var a=1;\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, minified := range []bool{false, true} {
+		report, _ := Run(context.Background(), Options{
+			Roots:     []string{dir},
+			License:   "MIT",
+			CheckOnly: true,
+			Minified:  minified,
+			Logger:    log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal("Run returned no report")
+		}
+		want := StatusMinified
+		if minified {
+			want = StatusMissing
+		}
+		for _, res := range report.Results {
+			if got := res.Status; filepath.Base(res.Path) != "app.js" && got != want {
+				t.Errorf("Minified %v: %s has status %v, want %v", minified, res.Path, got, want)
+			}
+		}
+		if got := report.Count(StatusMissing) + report.Count(StatusMinified); got != 3 {
+			t.Errorf("Minified %v: %d files processed, want 3", minified, got)
+		}
+	}
+
+	// files are skipped before being modified too
+	if _, err := Run(context.Background(), Options{
+		Roots:   []string{dir},
+		License: "MIT",
+		Logger:  log.New(ioutil.Discard, "", 0),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "bundle.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != files["bundle.js"] {
+		t.Error("minified bundle.js was modified")
+	}
+}
+
+// Test that existing license headers are identified.
+func TestHasLicense(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"", false},
+		{"This is my license", false},
+		{"SPDX: MIT", false},
+		{"Public domain", false},
+
+		{"Copyright 2000", true},
+		{"CoPyRiGhT 2000", true},
+		{"Subject to the terms of the Mozilla Public License", true},
+		{"SPDX-License-Identifier: MIT", true},
+		{"spdx-license-identifier: MIT", true},
+		{"SPDX-FileCopyrightText: 2020 Acme", true},
+		{"This code is released into the public domain.", true},
+		{"This is free and unencumbered software released into the public domain.", true},
+		{"Dedicated to the Public Domain under CC0.", true},
+		{"You should have received a copy of the CC0 Public Domain Dedication", true},
+	}
+
+	for _, tt := range tests {
+		b := []byte(tt.content)
+		if got := hasLicense(b); got != tt.want {
+			t.Errorf("hasLicense(%q) returned %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestFileMatches(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		path      string
+		wantMatch bool
+	}{
+		// basic single directory patterns
+		{"", "file.c", false},
+		{"*.c", "file.h", false},
+		{"*.c", "file.c", true},
+
+		// subdirectory patterns
+		{"*.c", "vendor/file.c", false},
+		{"**/*.c", "vendor/file.c", true},
+		{"vendor/**", "vendor/file.c", true},
+		{"vendor/**/*.c", "vendor/file.c", true},
+		{"vendor/**/*.c", "vendor/a/b/file.c", true},
+
+		// single character "?" match
+		{"*.?", "file.c", true},
+		{"*.?", "file.go", false},
+		{"*.??", "file.c", false},
+		{"*.??", "file.go", true},
+
+		// character classes - sets and ranges
+		{"*.[ch]", "file.c", true},
+		{"*.[ch]", "file.h", true},
+		{"*.[ch]", "file.ch", false},
+		{"*.[a-z]", "file.c", true},
+		{"*.[a-z]", "file.h", true},
+		{"*.[a-z]", "file.go", false},
+		{"*.[a-z]", "file.R", false},
+
+		// character classes - negations
+		{"*.[^ch]", "file.c", false},
+		{"*.[^ch]", "file.h", false},
+		{"*.[^ch]", "file.R", true},
+		{"*.[!ch]", "file.c", false},
+		{"*.[!ch]", "file.h", false},
+		{"*.[!ch]", "file.R", true},
+
+		// comma-separated alternative matches
+		{"*.{c,go}", "file.c", true},
+		{"*.{c,go}", "file.go", true},
+		{"*.{c,go}", "file.h", false},
+
+		// negating alternative matches
+		{"*.[^{c,go}]", "file.c", false},
+		{"*.[^{c,go}]", "file.go", false},
+		{"*.[^{c,go}]", "file.h", true},
+	}
+
+	for _, tt := range tests {
+		patterns := []string{tt.pattern}
+		if got := fileMatches(tt.path, patterns); got != tt.wantMatch {
+			t.Errorf("fileMatches(%q, %q) returned %v, want %v", tt.path, patterns, got, tt.wantMatch)
+		}
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	tests := []struct {
+		path        string
+		ignore      []string
+		keep        []string
+		wantIgnored bool
+	}{
+		{"file.yml", nil, nil, false},
+		{"file.yml", []string{"**/*.yml"}, nil, true},
+		{".github/workflows/ci.yml", []string{"**/*.yml"}, nil, true},
+
+		// keep patterns take precedence over ignore patterns
+		{".github/workflows/ci.yml", []string{"**/*.yml"}, presets["github-actions"].keep, false},
+		{"src/.github/workflows/ci.yaml", []string{"**/*.yaml"}, presets["github-actions"].keep, false},
+		{"config.yml", []string{"**/*.yml"}, presets["github-actions"].keep, true},
+
+		{".github/workflows/ci.yml", presets["no-github-actions"].ignore, nil, true},
+		{"/repo/action.yaml", presets["no-github-actions"].ignore, nil, true},
+		{".github/dependabot.yml", presets["no-github-actions"].ignore, nil, false},
+
+		{"infra/.terraform/modules/vpc/main.tf", presets["terraform"].ignore, nil, true},
+		{"infra/terraform.tfstate", presets["terraform"].ignore, nil, true},
+		{"infra/terraform.tfstate.backup", presets["terraform"].ignore, nil, true},
+		{"infra/.terraform.lock.hcl", presets["terraform"].ignore, nil, true},
+		{"docs/resources/instance.md", presets["terraform"].ignore, nil, true},
+		{"infra/main.tf", presets["terraform"].ignore, nil, false},
+		{"docs/design.md", presets["terraform"].ignore, nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := isIgnored(tt.path, tt.ignore, tt.keep); got != tt.wantIgnored {
+			t.Errorf("isIgnored(%q, %q, %q) returned %v, want %v", tt.path, tt.ignore, tt.keep, got, tt.wantIgnored)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "file.go")
+	if err := ioutil.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var diff strings.Builder
+	report, err := Run(context.Background(), Options{
+		Roots:   []string{dir},
+		Holder:  "Acme",
+		Year:    "2020",
+		License: "MIT",
+		DryRun:  true,
+		Diff:    &diff,
+		Logger:  log.New(ioutil.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.Paths(StatusModified); len(got) != 1 || got[0] != path {
+		t.Errorf("dry run reported modified files %q, want %q", got, path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "package main\n" {
+		t.Errorf("dry run wrote %q", b)
+	}
+	for _, want := range []string{"--- " + path + "\n", "+// Copyright (c) 2020 Acme\n", " package main\n"} {
+		if !strings.Contains(diff.String(), want) {
+			t.Errorf("dry run diff doesn't contain %q:\n%s", want, diff.String())
+		}
+	}
+}
+
+func TestHasExtension(t *testing.T) {
+	tests := []struct {
+		path string
+		exts []string
+		want bool
+	}{
+		{"a/b.go", nil, true},
+		{"a/b.go", []string{"go", "py"}, true},
+		{"a/b.PY", []string{"go", ".py"}, true},
+		{"a/b.ts", []string{"go", "py"}, false},
+		{"a/b.go.txt", []string{"go"}, false},
+		{"a/Dockerfile", []string{"dockerfile"}, true},
+		{"a/Makefile", []string{"go"}, false},
+	}
+	for _, tt := range tests {
+		if got := hasExtension(tt.path, tt.exts); got != tt.want {
+			t.Errorf("hasExtension(%q, %q) returned %v, want %v", tt.path, tt.exts, got, tt.want)
+		}
+	}
+}
+
+func TestOverlappingRoots(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(src, "file.go")
+	if err := ioutil.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Run(context.Background(), Options{
+		Roots:   []string{dir, src, src + "/", filepath.Join(dir, ".", "src"), path},
+		Holder:  "Acme",
+		Year:    "2020",
+		License: "MIT",
+		Logger:  log.New(ioutil.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 1 {
+		t.Errorf("processed %d files, want 1: %v", len(report.Results), report.Results)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(b), "Copyright"); n != 1 {
+		t.Errorf("file has %d license headers, want 1:\n%s", n, b)
+	}
+}
+
+func TestUniqueRoots(t *testing.T) {
+	got := uniqueRoots([]string{".", "./src", "src/", "src", "./", "lib"})
+	want := []string{".", "./src", "lib"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("uniqueRoots returned %q, want %q", got, want)
+	}
+}
+
+func TestIsIncluded(t *testing.T) {
+	tests := []struct {
+		path    string
+		include []string
+		want    bool
+	}{
+		{"a/b.go", nil, true},
+		{"a/b.go", []string{"**/*.go", "**/*.proto"}, true},
+		{"a/b.proto", []string{"**/*.go", "**/*.proto"}, true},
+		{"a/b.py", []string{"**/*.go", "**/*.proto"}, false},
+		{"b.go", []string{"a/**"}, false},
+	}
+	for _, tt := range tests {
+		if got := isIncluded(tt.path, tt.include); got != tt.want {
+			t.Errorf("isIncluded(%q, %q) returned %v, want %v", tt.path, tt.include, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultIgnores(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a.go", "vendor/b.go", "web/node_modules/c.js", "dist/d.js", "lib/third_party/e.c"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		root             string
+		noDefaultIgnores bool
+		want             int
+	}{
+		{dir, false, 1},
+		{dir, true, 5},
+		{filepath.Join(dir, "vendor"), false, 1},
+	}
+	for _, tt := range tests {
+		report, err := Run(context.Background(), Options{
+			Roots:            []string{tt.root},
+			License:          "MIT",
+			CheckOnly:        true,
+			NoDefaultIgnores: tt.noDefaultIgnores,
+			Logger:           log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal(err)
+		}
+		if got := len(report.Results); got != tt.want {
+			t.Errorf("Run(%s, NoDefaultIgnores: %v) processed %d files, want %d: %v", tt.root, tt.noDefaultIgnores, got, tt.want, report.Results)
+		}
+	}
+}
+
+func TestGlobRoots(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"src/a.go", "src/sub/b.go", "src/c.py", "other.go", "[x].go"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		root string
+		want []string
+	}{
+		{"src/**/*.go", []string{"src/a.go", "src/sub/b.go"}},
+		{"src/*", []string{"src/a.go", "src/c.py", "src/sub/b.go"}},
+		{"*.{go,py}", []string{"[x].go", "other.go"}},
+		{"[x].go", []string{"[x].go"}},
+		{"none/**/*.go", nil},
+	}
+	for _, tt := range tests {
+		report, err := Run(context.Background(), Options{
+			Roots:     []string{filepath.Join(dir, filepath.FromSlash(tt.root))},
+			License:   "MIT",
+			CheckOnly: true,
+			Logger:    log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, res := range report.Results {
+			rel, _ := filepath.Rel(dir, res.Path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("pattern %s processed %q, want %q", tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a.go", "b/b.go", "b/c/c.go"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for depth, want := range map[int]int{0: 3, 1: 1, 2: 2, 3: 3} {
+		report, err := Run(context.Background(), Options{
+			Roots:     []string{dir},
+			License:   "MIT",
+			CheckOnly: true,
+			MaxDepth:  depth,
+			Logger:    log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal(err)
+		}
+		if got := len(report.Results); got != want {
+			t.Errorf("MaxDepth %d processed %d files, want %d: %v", depth, got, want, report.Results)
+		}
+	}
+}
+
+func TestWalkWorkers(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 8; j++ {
+			path := filepath.Join(dir, fmt.Sprint("d", i), fmt.Sprint("e", j), "a.go")
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// overlapping roots name each file once
+	roots := []string{dir, filepath.Join(dir, "d3")}
+	for _, workers := range []int{1, 4} {
+		report, err := Run(context.Background(), Options{
+			Roots:       roots,
+			License:     "MIT",
+			CheckOnly:   true,
+			WalkWorkers: workers,
+			Logger:      log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal(err)
+		}
+		if got := len(report.Results); got != 64 {
+			t.Errorf("WalkWorkers %d processed %d files, want 64", workers, got)
+		}
+	}
+}
+
+func TestWorkerQueue(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for i := 0; i < 50; i++ {
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprint(i, ".go")), []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := Run(context.Background(), Options{
+		Roots:     []string{dir},
+		License:   "MIT",
+		CheckOnly: true,
+		Workers:   2,
+		QueueSize: 3,
+		Logger:    log.New(ioutil.Discard, "", 0),
+	})
+	if report == nil {
+		t.Fatal(err)
+	}
+	if got := len(report.Results); got != 50 {
+		t.Errorf("processed %d files, want 50", got)
+	}
+	q := report.Queue
+	if q.Workers != 2 || q.Capacity != 3 || q.Files != 50 {
+		t.Errorf("queue has %d workers, capacity %d and %d files, want 2, 3 and 50", q.Workers, q.Capacity, q.Files)
+	}
+	if q.MaxDepth > 3 || q.MeanDepth > 3 {
+		t.Errorf("queue depth exceeds its capacity: max %d, mean %f", q.MaxDepth, q.MeanDepth)
+	}
+}
+
+func TestMaxSize(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for name, size := range map[string]int{"small.go": 100, "big.go": 2000} {
+		content := "package a\n" + strings.Repeat("\n", size-10)
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var logs strings.Builder
+	report, err := Run(context.Background(), Options{
+		Roots:     []string{dir},
+		License:   "MIT",
+		CheckOnly: true,
+		MaxSize:   1000,
+		Logger:    log.New(&logs, "", 0),
+	})
+	if report == nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 1 || filepath.Base(report.Results[0].Path) != "small.go" {
+		t.Errorf("processed %v, want small.go only", report.Results)
+	}
+	if !strings.Contains(logs.String(), "big.go: 2000 bytes, larger than the maximum size of 1000") {
+		t.Errorf("big.go skipped without a log line: %q", logs.String())
+	}
+}
+
+func TestHidden(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a.go", ".b.go", ".idea/c.go", ".github/workflows/d.yml"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		root          string
+		hidden        HiddenPolicy
+		includeHidden []string
+		want          int
+	}{
+		{dir, HiddenSkip, nil, 1},
+		{dir, HiddenInclude, nil, 4},
+		{dir, HiddenSkip, []string{"**/.github/**"}, 2},
+		{filepath.Join(dir, ".idea"), HiddenSkip, nil, 1},
+	}
+	for _, tt := range tests {
+		report, err := Run(context.Background(), Options{
+			Roots:         []string{tt.root},
+			License:       "MIT",
+			CheckOnly:     true,
+			Hidden:        tt.hidden,
+			IncludeHidden: tt.includeHidden,
+			Logger:        log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal(err)
+		}
+		if got := len(report.Results); got != tt.want {
+			t.Errorf("Run(%s, Hidden: %q, IncludeHidden: %q) processed %d files, want %d: %v", tt.root, tt.hidden, tt.includeHidden, got, tt.want, report.Results)
+		}
+	}
+}
+
+func TestVCSDirs(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a.go", ".git/hooks/b.sh", ".hg/c.py"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for walkVCS, want := range map[bool]int{false: 1, true: 3} {
+		report, err := Run(context.Background(), Options{
+			Roots:            []string{dir},
+			License:          "MIT",
+			CheckOnly:        true,
+			Hidden:           HiddenInclude,
+			NoDefaultIgnores: true,
+			WalkVCS:          walkVCS,
+			Logger:           log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal(err)
+		}
+		if got := len(report.Results); got != want {
+			t.Errorf("Run(WalkVCS: %v) processed %d files, want %d: %v", walkVCS, got, want, report.Results)
+		}
+	}
+}
+
+func TestReadPooled(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	r := &runner{}
+	for _, content := range []string{"package a\n", "package bb\n", ""} {
+		path := filepath.Join(dir, "a.go")
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		b, release, err := r.readPooled(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Errorf("readPooled returned %q, want %q", b, content)
+		}
+		release()
+	}
+	if _, _, err := r.readPooled(filepath.Join(dir, "missing.go")); err == nil {
+		t.Error("readPooled of a missing file returned no error")
+	}
+}
+
+func BenchmarkCheckOnly(b *testing.B) {
+	dir := tempDir(b)
+	defer os.RemoveAll(dir)
+	for i := 0; i < 100; i++ {
+		content := fmt.Sprintf("// Copyright 2020 Acme\n\npackage a\n\nvar x%d = %q\n", i, strings.Repeat("x", 4096))
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", i)), []byte(content), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	opts := Options{Roots: []string{dir}, License: "MIT", CheckOnly: true, Logger: log.New(ioutil.Discard, "", 0)}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Run(context.Background(), opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestReportStats(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{
+		"a.go":   "// Copyright 2020 Google LLC\n// Licensed under the Apache License, Version 2.0\n\npackage a\n",
+		"b.go":   "package b\n",
+		"c.py":   "# Copyright 2021 Acme\n# SPDX-License-Identifier: MIT\n",
+		"d.json": "{}\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, _ := Run(context.Background(), Options{
+		Roots:     []string{dir},
+		License:   "apache",
+		CheckOnly: true,
+		Logger:    log.New(ioutil.Discard, "", 0),
+	})
+	if report == nil {
+		t.Fatal("Run returned no report")
+	}
+	want := Stats{
+		Extensions: map[string]map[Status]int{
+			"go":   {StatusOK: 1, StatusMissing: 1},
+			"py":   {StatusOK: 1},
+			"json": {StatusSkipped: 1},
+		},
+		Licenses: map[string]int{"Apache-2.0": 1, "MIT": 1},
+		Holders:  map[string]int{"Google LLC": 1, "Acme": 1},
+	}
+	if got := report.Stats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats returned %v, want %v", got, want)
+	}
+}
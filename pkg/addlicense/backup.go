@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// BackupSuffix is the suffix of the backups saved next to modified files.
+const BackupSuffix = ".orig"
+
+// backups saves the original contents of the files modified by a run, once
+// per file.
+type backups struct {
+	dir   string // absolute path of the backup directory, or empty
+	mu    sync.Mutex
+	saved map[string]bool // absolute paths of the files saved so far
+}
+
+// newBackups returns the backups of a run, saved under dir, which is created
+// if missing, or next to the files if dir is empty.
+func newBackups(dir string) (*backups, error) {
+	bk := &backups{saved: make(map[string]bool)}
+	if dir == "" {
+		return bk, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var err error
+	bk.dir, err = filepath.Abs(dir)
+	return bk, err
+}
+
+// path returns the path of the backup of the file at path, see mirrorPath.
+func (bk *backups) path(path string) string {
+	if bk.dir == "" {
+		return path + BackupSuffix
+	}
+	return mirrorPath(bk.dir, path)
+}
+
+// save saves old, the contents of the file at path about to be modified,
+// unless they were saved already: a file updated several times during a run
+// keeps the backup of its contents before the run.
+func (bk *backups) save(path string, mode os.FileMode, old []byte) error {
+	abs := canonicalPath(path)
+	bk.mu.Lock()
+	defer bk.mu.Unlock()
+	if bk.saved[abs] {
+		return nil
+	}
+	dst := bk.path(path)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(dst, old, mode.Perm()); err != nil {
+		return err
+	}
+	bk.saved[abs] = true
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.go")
+	const orig = "package a\n"
+	if err := ioutil.WriteFile(path, []byte(orig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the header and the footer are written separately, but the backup holds
+	// the contents before both
+	_, err := Run(context.Background(), Options{
+		Roots:   []string{dir},
+		Holder:  "Acme",
+		License: "MIT",
+		SPDX:    SPDXOnly,
+		Footers: map[string]string{"go": "END"},
+		Backup:  true,
+		Logger:  log.New(ioutil.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path + BackupSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != orig {
+		t.Errorf("backup holds %q, want %q", b, orig)
+	}
+}
+
+func TestBackupDir(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "src", "a.go")
+	const orig = "package a\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(orig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for i := 0; i < 2; i++ {
+		// the backups of the first run are not processed by the second
+		report, err := Run(context.Background(), Options{
+			Roots:     []string{"."},
+			Holder:    "Acme",
+			License:   "MIT",
+			SPDX:      SPDXOnly,
+			BackupDir: "backup",
+			Logger:    log.New(ioutil.Discard, "", 0),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Results) != 1 {
+			t.Errorf("run %d processed %d files, want 1: %v", i+1, len(report.Results), report.Results)
+		}
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "backup", "src", "a.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != orig {
+		t.Errorf("backup holds %q, want %q", b, orig)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"log"
+)
+
+// bufferRunner returns a runner processing b, the unsaved contents of the file
+// at path, in place of the file itself. Updates are recorded as in a dry run,
+// and the file is never written.
+func bufferRunner(opts Options, path string, b []byte) (*runner, error) {
+	opts.DryRun = true
+	opts.Diff = ioutil.Discard
+	opts.Snapshot = ""
+	if opts.Logger == nil {
+		opts.Logger = log.New(ioutil.Discard, "", 0)
+	}
+	r, err := newRunner(opts)
+	if err != nil {
+		return nil, err
+	}
+	r.sources = map[string][]byte{path: b}
+	return r, nil
+}
+
+// CheckBuffer checks b, the possibly unsaved contents of the file at path, like
+// a run in check only mode with opts would check the file, for editors to
+// report missing headers as the user types. Missing, wrong, forbidden and
+// duplicate license headers and missing footers are reported by the status
+// alone.
+func CheckBuffer(opts Options, path string, b []byte) (Status, error) {
+	opts.CheckOnly = true
+	r, err := bufferRunner(opts, path, b)
+	if err != nil {
+		return StatusError, err
+	}
+	status, err := r.updateFile(&file{path: path, mode: 0644, log: &fileLog{}})
+	if err == ErrMissingLicense || err == ErrMissingFooter || err == ErrWrongLicense || err == ErrForbiddenLicense {
+		err = nil
+	}
+	return status, err
+}
+
+// FixBuffer returns b, the possibly unsaved contents of the file at path, with
+// the updates a run with opts would make to the file, and the status of the
+// file. The file itself is neither read nor written.
+func FixBuffer(opts Options, path string, b []byte) ([]byte, Status, error) {
+	opts.CheckOnly = false
+	r, err := bufferRunner(opts, path, b)
+	if err != nil {
+		return nil, StatusError, err
+	}
+	status, err := r.updateFile(&file{path: path, mode: 0644, log: &fileLog{}})
+	if err != nil {
+		return nil, status, err
+	}
+	if nb, ok := r.pending[path]; ok {
+		return nb, status, nil
+	}
+	return b, status, nil
+}
+
+// RenderHeader returns the license header a run with opts adds to the file at
+// path, in the comment style of the file, or nil if the file type is unknown.
+func RenderHeader(opts Options, path string) ([]byte, error) {
+	r, err := bufferRunner(opts, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, data, _ := r.license(path)
+	return licenseHeader(r.style(path), tmpl, data)
+}
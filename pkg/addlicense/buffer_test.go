@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	opts := Options{Holder: "Acme", Year: "2020", License: "MIT"}
+	// the file doesn't exist: only the buffer is processed
+	path := "does/not/exist.go"
+	src := []byte("package main\n")
+
+	status, err := CheckBuffer(opts, path, src)
+	if err != nil || status != StatusMissing {
+		t.Errorf("CheckBuffer returned %v, %v, want %v", status, err, StatusMissing)
+	}
+
+	fixed, status, err := FixBuffer(opts, path, src)
+	if err != nil || status != StatusModified {
+		t.Fatalf("FixBuffer returned %v, %v, want %v", status, err, StatusModified)
+	}
+	if !strings.HasPrefix(string(fixed), "// Copyright (c) 2020 Acme\n") || !strings.HasSuffix(string(fixed), "\npackage main\n") {
+		t.Errorf("FixBuffer returned:\n%s", fixed)
+	}
+
+	if status, err := CheckBuffer(opts, path, fixed); err != nil || status != StatusOK {
+		t.Errorf("CheckBuffer of the fixed buffer returned %v, %v, want %v", status, err, StatusOK)
+	}
+	if again, status, err := FixBuffer(opts, path, fixed); err != nil || status != StatusOK || string(again) != string(fixed) {
+		t.Errorf("FixBuffer of the fixed buffer returned %v, %v:\n%s", status, err, again)
+	}
+
+	header, err := RenderHeader(opts, "a.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(header), "# Copyright (c) 2020 Acme\n") {
+		t.Errorf("RenderHeader returned:\n%s", header)
+	}
+	if header, err := RenderHeader(opts, "a.unknown"); err != nil || header != nil {
+		t.Errorf("RenderHeader of an unknown file type returned %q, %v", header, err)
+	}
+}
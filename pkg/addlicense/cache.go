@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// cacheVersion is the version of the cache file format and of the checks,
+// bumped whenever the outcome of processing unchanged files may change.
+const cacheVersion = 1
+
+// cacheFile is the contents of the cache file of a run, see Options.Cache.
+type cacheFile struct {
+	Version int `json:"version"`
+	// Config is a hash of the options of the run the results come from, see
+	// cacheConfig: the results of other configurations don't apply.
+	Config string                `json:"config"`
+	Files  map[string]cacheEntry `json:"files"`
+}
+
+// cacheEntry records the outcome of processing a file.
+type cacheEntry struct {
+	Hash    string   `json:"hash"` // SHA-256 of the contents of the file
+	Status  Status   `json:"status"`
+	License string   `json:"license,omitempty"`
+	Holder  string   `json:"holder,omitempty"`
+	Log     []string `json:"log,omitempty"`
+}
+
+// cache holds the results of the previous run, read from the cache file, and
+// those of the current run, which replace them once it is over.
+type cache struct {
+	path   string
+	config string
+	old    map[string]cacheEntry
+
+	mu  sync.Mutex
+	new map[string]cacheEntry
+}
+
+// loadCache reads the cache file at path, if any, keeping its results only if
+// they were recorded with the same config.
+func loadCache(path, config string) (*cache, error) {
+	c := &cache{path: path, config: config, old: make(map[string]cacheEntry), new: make(map[string]cacheEntry)}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache: %w", err)
+	}
+	var f cacheFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		// a corrupted cache is rebuilt
+		return c, nil
+	}
+	if f.Version == cacheVersion && f.Config == config && f.Files != nil {
+		c.old = f.Files
+	}
+	return c, nil
+}
+
+// lookup returns the hash of the contents of the file at path and, if the
+// file is unchanged since the previous run, the result of that run, which is
+// kept for the next one.
+func (c *cache) lookup(path string) (hash string, entry *cacheEntry, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", nil, err
+	}
+	hash = hex.EncodeToString(h.Sum(nil))
+	if e, ok := c.old[path]; ok && e.Hash == hash {
+		// the result stays valid for the next run
+		c.mu.Lock()
+		c.new[path] = e
+		c.mu.Unlock()
+		return hash, &e, nil
+	}
+	return hash, nil, nil
+}
+
+// store records the result of processing the file at path, whose contents
+// hash to hash, if it may be reused: files that were modified or failed are
+// processed again.
+func (c *cache) store(path, hash string, e cacheEntry) {
+	switch e.Status {
+	case StatusModified, StatusError, StatusWarning:
+		return
+	}
+	e.Hash = hash
+	c.mu.Lock()
+	c.new[path] = e
+	c.mu.Unlock()
+}
+
+// write replaces the cache file with the results of the current run. Files
+// not processed by the run, such as deleted ones, are dropped.
+func (c *cache) write() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := json.Marshal(cacheFile{Version: cacheVersion, Config: c.config, Files: c.new})
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("cache: %w", err)
+		}
+	}
+	return replaceFile(c.path, 0644, func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+}
+
+// cacheConfig returns a hash of the options of the run that affect the
+// outcome of processing a file, footer templates included, along with tmpl,
+// the text of its license template.
+func (r *runner) cacheConfig(tmpl string) string {
+	o := r.opts
+	// which files are processed and how doesn't change their outcome
+	o.Roots, o.Ignore, o.Include, o.OnlyExtensions = nil, nil, nil, nil
+	o.Hidden, o.IncludeHidden, o.MaxDepth, o.MaxSize = "", nil, 0, 0
+	o.WalkWorkers, o.FollowSymlinks, o.NoDefaultIgnores, o.WalkVCS = 0, false, false, false
+	o.Presets, o.GitStaged, o.GitAddedOnly, o.GitTracked, o.GitSince = nil, false, false, false, ""
+	o.FileTimeout, o.Workers, o.QueueSize, o.Snapshot, o.Cache = 0, 0, 0, "", ""
+	o.Backup, o.BackupDir, o.OutputDir, o.TolerateErrors = false, "", "", 0
+	o.DryRun, o.Diff, o.Logger = false, nil, nil
+	o.Progress, o.ProgressInterval, o.OnResult, o.LogStyle = nil, 0, nil, nil
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%+v\n%s\n", cacheVersion, o, tmpl)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// statusError returns the error of the files with a status that is a check
+// failure, or nil.
+func statusError(status Status) error {
+	switch status {
+	case StatusMissing:
+		return ErrMissingLicense
+	case StatusMissingFooter:
+		return ErrMissingFooter
+	case StatusWrongLicense:
+		return ErrWrongLicense
+	case StatusForbidden:
+		return ErrForbiddenLicense
+	}
+	return nil
+}
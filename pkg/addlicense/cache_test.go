@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	for path, content := range map[string]string{
+		a: "package a\n",
+		b: "// Copyright 2020 Acme\n\npackage b\n",
+	} {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cachePath := filepath.Join(dir, "cache", "addlicense.json")
+	run := func(license string) map[string]Status {
+		report, _ := Run(context.Background(), Options{
+			Roots:     []string{a, b},
+			License:   license,
+			CheckOnly: true,
+			Cache:     cachePath,
+			Logger:    log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal("Run returned no report")
+		}
+		statuses := make(map[string]Status)
+		for _, res := range report.Results {
+			statuses[filepath.Base(res.Path)] = res.Status
+		}
+		return statuses
+	}
+	// tamper with the cached status of b.go, to tell whether it is reused
+	tamper := func() {
+		data, err := ioutil.ReadFile(cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var f cacheFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			t.Fatal(err)
+		}
+		if len(f.Files) != 2 {
+			t.Fatalf("cache records %d files, want 2", len(f.Files))
+		}
+		e := f.Files[b]
+		e.Status = StatusDuplicate
+		f.Files[b] = e
+		if data, err = json.Marshal(f); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(cachePath, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := run("MIT"); got["a.go"] != StatusMissing || got["b.go"] != StatusOK {
+		t.Fatalf("first run returned %v", got)
+	}
+	tamper()
+	if got := run("MIT"); got["a.go"] != StatusMissing || got["b.go"] != StatusDuplicate {
+		t.Errorf("run of unchanged files returned %v, want the cached results", got)
+	}
+
+	// changed options invalidate the cache
+	tamper()
+	if got := run("apache"); got["b.go"] != StatusOK {
+		t.Errorf("run with other options returned %v, want b.go checked again", got)
+	}
+
+	// changed files are checked again
+	tamper()
+	if err := ioutil.WriteFile(b, []byte("// Copyright 2021 Acme\n\npackage b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := run("apache"); got["b.go"] != StatusOK {
+		t.Errorf("run of a changed file returned %v, want b.go checked again", got)
+	}
+}
+
+func TestCacheConfigCallbacks(t *testing.T) {
+	var n int
+	a := &runner{opts: Options{License: "MIT"}}
+	b := &runner{opts: Options{
+		License:  "MIT",
+		Progress: func(Progress) { n++ },
+		OnResult: func(Result) { n-- },
+		LogStyle: func(_ Status, line string) string { return line },
+	}}
+	if a.cacheConfig("tmpl") != b.cacheConfig("tmpl") {
+		t.Error("callbacks of the run changed the cache configuration")
+	}
+}
+
+func TestCacheConfigFooters(t *testing.T) {
+	a := &runner{opts: Options{License: "MIT", Footers: map[string]string{"c": "END OF FILE"}}}
+	b := &runner{opts: Options{License: "MIT", Footers: map[string]string{"c": "EOF"}}}
+	if a.cacheConfig("tmpl") == b.cacheConfig("tmpl") {
+		t.Error("footer templates didn't change the cache configuration")
+	}
+}
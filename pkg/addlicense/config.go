@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the name of the configuration file read from the
+// current directory by the addlicense command, and written by its init
+// command.
+const DefaultConfigFile = ".addlicense.yaml"
+
+// DefaultIgnoreFile is the name of the file of ignore patterns read from the
+// current directory by the addlicense command.
+const DefaultIgnoreFile = ".addlicenseignore"
+
+// Config holds the settings of a configuration file, which provide the
+// default values of the command line flags.
+type Config struct {
+	License   string   `yaml:"license,omitempty"`
+	Holder    string   `yaml:"holder,omitempty"`
+	Year      string   `yaml:"year,omitempty"`
+	Notice    string   `yaml:"notice,omitempty"`
+	SPDX      SPDXMode `yaml:"spdx,omitempty"`
+	KeepShort bool     `yaml:"keep_short,omitempty"`
+	Ignore    []string `yaml:"ignore,omitempty"`
+	Include   []string `yaml:"include,omitempty"`
+	Presets   []string `yaml:"presets,omitempty"`
+	Markers   []string `yaml:"markers,omitempty"`
+	// Footers maps file extensions, or "*" for all files, to the paths of
+	// footer templates.
+	Footers map[string]string `yaml:"footers,omitempty"`
+	// Licenses maps file patterns to the licenses of the matching files, for
+	// subtrees using another license than License.
+	Licenses map[string]string `yaml:"licenses,omitempty"`
+	// InsertAfter maps file patterns to regular expressions matching the
+	// line after which license headers are added to the matching files.
+	InsertAfter map[string]string `yaml:"insert_after,omitempty"`
+	// ExtStyles maps file extensions to the names of their comment styles.
+	ExtStyles map[string]string `yaml:"ext_styles,omitempty"`
+	// CommentStyles maps file extensions to the definitions of custom
+	// comment styles, see Options.CommentStyles.
+	CommentStyles map[string]string `yaml:"comment_styles,omitempty"`
+}
+
+// ReadConfig reads the configuration file at path. Unknown settings are
+// reported as errors, so that typos don't go unnoticed.
+func ReadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && len(bytes.TrimSpace(b)) > 0 {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if c.SPDX != SPDXOff && c.SPDX != SPDXOn && c.SPDX != SPDXOnly && c.SPDX != SPDXTags && c.SPDX != SPDXShort {
+		return nil, fmt.Errorf("%s: spdx expects '%v', '%v', '%v' or '%v'", path, SPDXOn, SPDXOnly, SPDXTags, SPDXShort)
+	}
+	return &c, nil
+}
+
+// ReadIgnoreFile reads the ignore patterns of the file at path: one pattern
+// per line, ignoring blank lines and comments starting with '#'.
+func ReadIgnoreFile(path string) ([]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var patterns []string
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !doublestar.ValidatePattern(line) {
+			return nil, fmt.Errorf("%s:%d: pattern %q is not valid", path, i+1, line)
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// WriteConfig writes c to the configuration file at path.
+func WriteConfig(path string, c *Config) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	b = append([]byte("# addlicense configuration, see https://github.com/google/addlicense\n"), b...)
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// Options returns the options of a run processing roots with the settings of
+// c. The year defaults to the current year, like for the addlicense command,
+// and "git" derives the years of each file from its git history.
+func (c *Config) Options(roots ...string) Options {
+	year, gitYears := c.Year, c.Year == "git"
+	if year == "" || gitYears {
+		year = strconv.Itoa(time.Now().Year())
+	}
+	return Options{
+		Roots:         roots,
+		Holder:        c.Holder,
+		Year:          year,
+		GitYears:      gitYears,
+		License:       c.License,
+		SPDX:          c.SPDX,
+		KeepShort:     c.KeepShort,
+		Ignore:        c.Ignore,
+		Include:       c.Include,
+		Presets:       c.Presets,
+		Markers:       c.Markers,
+		Licenses:      LicenseRules(c.Licenses),
+		ExtStyles:     c.ExtStyles,
+		CommentStyles: c.CommentStyles,
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	path := filepath.Join(tempDir(t), DefaultConfigFile)
+	want := &Config{
+		License: "MIT",
+		Holder:  "Acme Corp",
+		SPDX:    SPDXOnly,
+		Ignore:  []string{"**/vendor/**"},
+
+		Licenses:      map[string]string{"sdk/**": "MIT"},
+		InsertAfter:   map[string]string{"**/*.c": "^#include <config.h>$"},
+		CommentStyles: map[string]string{"foo": "# ", "bar": "/*, * , */"},
+	}
+	if err := WriteConfig(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadConfig returned %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"licence: MIT\n", "spdx: maybe\n", "ignore: [\n"} {
+		if err := ioutil.WriteFile(path, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadConfig(path); err == nil {
+			t.Errorf("ReadConfig of %q returned no error", bad)
+		}
+	}
+}
+
+func TestReadIgnoreFile(t *testing.T) {
+	path := filepath.Join(tempDir(t), DefaultIgnoreFile)
+	content := "# generated code\n**/*.pb.go\n\n  vendor/**  \n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadIgnoreFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"**/*.pb.go", "vendor/**"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadIgnoreFile returned %q, want %q", got, want)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("ok/**\n[bad\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadIgnoreFile(path); err == nil {
+		t.Error("ReadIgnoreFile accepted an invalid pattern")
+	}
+}
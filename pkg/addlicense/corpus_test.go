@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// corpusDir holds miniature replicas of the layouts of real projects: a Go
+// module, a Node app, a Python package, a Bazel workspace, a Terraform stack,
+// an R and Julia analysis, a Lua game, a Perl distribution with Raku scripts,
+// Windows batch and PowerShell scripts, a Cargo workspace, a Java service, a
+// LaTeX paper, a Fortran solver, an Ada program, a Free Pascal program,
+// assembly firmware and a Visual Basic app, with their vendored, generated and
+// binary files.
+const corpusDir = "testdata/corpus"
+
+// TestCorpus runs addlicense over the corpus and verifies that adding license
+// headers is idempotent, that the result passes the check, and that removing
+// the headers restores every file byte for byte.
+func TestCorpus(t *testing.T) {
+	layouts, err := ioutil.ReadDir(corpusDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, layout := range layouts {
+		layout := layout.Name()
+		t.Run(layout, func(t *testing.T) {
+			orig := readTree(t, filepath.Join(corpusDir, layout))
+			dir := tempDir(t)
+			defer os.RemoveAll(dir)
+			writeTree(t, dir, orig)
+			opts := Options{
+				Roots:    []string{dir},
+				Holder:   "Acme",
+				Year:     "2026",
+				License:  "Apache-2.0",
+				VerifyGo: true,
+				Logger:   log.New(ioutil.Discard, "", 0),
+			}
+
+			report, err := Run(context.Background(), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Count(StatusModified) == 0 {
+				t.Fatal("no file was modified")
+			}
+			added := readTree(t, dir)
+			for path, content := range orig {
+				if inSkippedDir(path) && added[path] != content {
+					t.Errorf("%s was modified, although its directory is skipped", path)
+				}
+			}
+
+			report, err = Run(context.Background(), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if paths := report.Paths(StatusModified); len(paths) > 0 {
+				t.Errorf("second run modified %q", paths)
+			}
+			compareTrees(t, "second run", readTree(t, dir), added)
+
+			check := opts
+			check.CheckOnly = true
+			if _, err := Run(context.Background(), check); err != nil {
+				t.Errorf("check failed: %v", err)
+			}
+
+			remove := opts
+			remove.Remove = true
+			if _, err := Run(context.Background(), remove); err != nil {
+				t.Fatal(err)
+			}
+			compareTrees(t, "removing headers", readTree(t, dir), orig)
+		})
+	}
+}
+
+// inSkippedDir reports whether path, relative to the root of a tree, is in a
+// directory that is skipped by default.
+func inSkippedDir(path string) bool {
+	dirs := strings.Split(filepath.Dir(path), string(filepath.Separator))
+	for _, d := range dirs {
+		if isDefaultIgnoredDir(d) || isHidden(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// readTree returns the contents of the files of the tree at root, by path
+// relative to root.
+func readTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		files[rel] = string(b)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// writeTree writes files, by path relative to root, under root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// compareTrees reports the differences between the files of two trees, as
+// returned by readTree, after step.
+func compareTrees(t *testing.T, step string, got, want map[string]string) {
+	t.Helper()
+	var paths []string
+	for path := range want {
+		paths = append(paths, path)
+	}
+	for path := range got {
+		if _, ok := want[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		g, gok := got[path]
+		w, wok := want[path]
+		switch {
+		case !gok:
+			t.Errorf("after %s, %s is missing", step, path)
+		case !wok:
+			t.Errorf("after %s, %s was created", step, path)
+		case g != w:
+			t.Errorf("after %s, %s holds\n%q\nwant\n%q", step, path, g, w)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines surrounding each hunk.
+const diffContext = 3
+
+// maxDiffLines bounds the size of the changed region that is diffed line by
+// line. Larger regions are shown as a whole being replaced.
+const maxDiffLines = 2000
+
+// diffLine is a line of a unified diff: kind is ' ', '-' or '+'.
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff returns a unified diff turning a into b, with the given file
+// names in its header, or an empty string if they are equal.
+//
+// Common leading and trailing lines are skipped before diffing, which keeps
+// the typical license header edit cheap even for very large files.
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	al, bl := splitLines(a), splitLines(b)
+	pre := 0
+	for pre < len(al) && pre < len(bl) && al[pre] == bl[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(al)-pre && suf < len(bl)-pre && al[len(al)-1-suf] == bl[len(bl)-1-suf] {
+		suf++
+	}
+
+	var lines []diffLine
+	for _, l := range al[:pre] {
+		lines = append(lines, diffLine{' ', l})
+	}
+	lines = append(lines, diffLines(al[pre:len(al)-suf], bl[pre:len(bl)-suf])...)
+	for _, l := range al[len(al)-suf:] {
+		lines = append(lines, diffLine{' ', l})
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+	for i := 0; i < len(lines); {
+		if lines[i].kind == ' ' {
+			i++
+			continue
+		}
+		// extend the hunk while changes are close enough to share context
+		first, last := i, i
+		for j := i + 1; j < len(lines) && j <= last+2*diffContext; j++ {
+			if lines[j].kind != ' ' {
+				last = j
+			}
+		}
+		start, end := first-diffContext, last+diffContext+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		writeHunk(&out, lines, start, end)
+		i = end
+	}
+	return out.String()
+}
+
+// writeHunk writes the hunk made of lines[start:end] to out.
+func writeHunk(out *strings.Builder, lines []diffLine, start, end int) {
+	aStart, bStart := 0, 0
+	for _, l := range lines[:start] {
+		if l.kind != '+' {
+			aStart++
+		}
+		if l.kind != '-' {
+			bStart++
+		}
+	}
+	aCount, bCount := 0, 0
+	for _, l := range lines[start:end] {
+		if l.kind != '+' {
+			aCount++
+		}
+		if l.kind != '-' {
+			bCount++
+		}
+	}
+	// an empty range refers to the line preceding it
+	if aCount > 0 {
+		aStart++
+	}
+	if bCount > 0 {
+		bStart++
+	}
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+	for _, l := range lines[start:end] {
+		out.WriteByte(l.kind)
+		out.WriteString(l.text)
+		if !strings.HasSuffix(l.text, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// diffLines returns the edit script turning a into b, based on their longest
+// common subsequence.
+func diffLines(a, b []string) []diffLine {
+	var lines []diffLine
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		for _, l := range a {
+			lines = append(lines, diffLine{'-', l})
+		}
+		for _, l := range b {
+			lines = append(lines, diffLine{'+', l})
+		}
+		return lines
+	}
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// splitLines splits b after each newline.
+func splitLines(b []byte) []string {
+	var lines []string
+	for off := 0; off < len(b); {
+		line, next := nextLine(b, off)
+		lines = append(lines, string(line))
+		off = next
+	}
+	return lines
+}
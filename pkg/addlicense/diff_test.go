@@ -0,0 +1,57 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"same\n", "same\n", ""},
+		{
+			"package main\n",
+			"// Copyright\n\npackage main\n",
+			"--- a\n+++ b\n@@ -1,1 +1,3 @@\n+// Copyright\n+\n package main\n",
+		},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			"1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			"--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			"one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			"--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			"a\nb",
+			"a\nc",
+			"--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			"x\n",
+			"",
+			"--- a\n+++ b\n@@ -1,1 +0,0 @@\n-x\n",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := unifiedDiff("a", "b", []byte(tt.a), []byte(tt.b)); got != tt.want {
+			t.Errorf("unifiedDiff(%q, %q) returned:\n%s\nwant:\n%s", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
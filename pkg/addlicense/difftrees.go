@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DiffTrees writes to w a unified diff of the license headers of each file
+// found in both a and b whose headers differ, and returns the number of such
+// files. Only files of a known type are compared, and paths relative to each
+// tree that match an ignore pattern are skipped.
+func DiffTrees(w io.Writer, a, b string, ignore []string) (int, error) {
+	aFiles, err := treeFiles(a, ignore)
+	if err != nil {
+		return 0, err
+	}
+	bFiles, err := treeFiles(b, ignore)
+	if err != nil {
+		return 0, err
+	}
+	var common []string
+	for rel := range aFiles {
+		if bFiles[rel] {
+			common = append(common, rel)
+		}
+	}
+	sort.Strings(common)
+
+	n := 0
+	for _, rel := range common {
+		aHeader, err := fileLicenseBlock(filepath.Join(a, rel))
+		if err != nil {
+			return n, err
+		}
+		bHeader, err := fileLicenseBlock(filepath.Join(b, rel))
+		if err != nil {
+			return n, err
+		}
+		if d := unifiedDiff(filepath.Join(a, rel), filepath.Join(b, rel), aHeader, bHeader); d != "" {
+			fmt.Fprint(w, d)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// treeFiles returns the slash separated paths, relative to root, of the files
+// of a known type found in root.
+func treeFiles(root string, ignore []string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() || fileCommentStyle(path) == nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !fileMatches(rel, ignore) {
+			files[rel] = true
+		}
+		return nil
+	})
+	return files, err
+}
+
+// fileLicenseBlock returns the license header of the file at path, or nil if
+// it has none.
+func fileLicenseBlock(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	start, end, ok := licenseBlock(fileCommentStyle(path), path, b, licenseMarkers)
+	if !ok {
+		return nil, nil
+	}
+	return b[start:end], nil
+}
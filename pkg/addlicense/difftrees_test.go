@@ -0,0 +1,66 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffTrees(t *testing.T) {
+	a, b := tempDir(t), tempDir(t)
+	files := []struct {
+		name   string
+		a, b   string
+		differ bool
+	}{
+		{"same.go", "// Copyright 2020 Acme\n\npackage a\n", "// Copyright 2020 Acme\n\npackage b\n", false},
+		{"holder.go", "// Copyright 2020 Acme\n", "// Copyright 2020 Acme Corp\n", true},
+		{"missing.go", "// Copyright 2020 Acme\n", "package main\n", true},
+		{"vendor/lib.go", "// Copyright 2020 Acme\n", "// Copyright 2020 Other\n", false},
+		{"unknown.ext", "Copyright 2020 Acme\n", "Copyright 2020 Other\n", false},
+	}
+	for _, f := range files {
+		for dir, content := range map[string]string{a: f.a, b: f.b} {
+			path := filepath.Join(dir, f.name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(a, "only_a.go"), []byte("// Copyright 2020 Acme\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	n, err := DiffTrees(&out, a, b, []string{"vendor/**"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("diffTrees returned %d differences, want 2\n%s", n, out.String())
+	}
+	for _, f := range files {
+		if got := strings.Contains(out.String(), f.name); got != f.differ {
+			t.Errorf("diffTrees output mentions %s: %v, want %v\n%s", f.name, got, f.differ, out.String())
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"os"
+)
+
+// duplicateLicense locates a second license header stacked right below the
+// license header of b, separated from it by blank lines only. b holds the
+// contents of the file at path, commented in style. It returns the offsets of
+// the redundant part, from the end of the first header to the end of the
+// second one, whether both headers have the same text, ignoring whitespace,
+// and false if there is no such header.
+func duplicateLicense(style *commentStyle, path string, b []byte, markers [][]byte) (start, end int, identical, ok bool) {
+	firstStart, firstEnd, ok := licenseBlock(style, path, b, markers)
+	if !ok {
+		return 0, 0, false, false
+	}
+	secondStart, secondEnd, ok := commentBlock(style, b, firstEnd)
+	if !ok || !containsMarker(b[secondStart:secondEnd], markers) {
+		return 0, 0, false, false
+	}
+	identical = bytes.Equal(normalizeSpace(b[firstStart:firstEnd]), normalizeSpace(b[secondStart:secondEnd]))
+	return firstEnd, secondEnd, identical, true
+}
+
+// normalizeSpace returns b with runs of whitespace replaced by a single space
+// and leading and trailing whitespace removed.
+func normalizeSpace(b []byte) []byte {
+	return bytes.Join(bytes.Fields(b), []byte(" "))
+}
+
+// fixDuplicates removes the redundant copy of the license header of the file at
+// path, if stacked right below it. Headers that differ from the first one are
+// only reported to l, since removing them could lose information.
+//
+// It returns true if the file was updated.
+func (r *runner) fixDuplicates(l logger, path string, fmode os.FileMode) (bool, error) {
+	b, err := r.readFile(path)
+	if err != nil {
+		return false, err
+	}
+	start, end, identical, ok := duplicateLicense(r.style(path), path, b, r.markers)
+	if !ok {
+		return false, nil
+	}
+	if !identical {
+		l.Printf("%s: stacked license headers differ, not removing the second one", path)
+		return false, nil
+	}
+	nb := make([]byte, 0, len(b)-(end-start))
+	nb = append(nb, b[:start]...)
+	return true, r.writeFile(path, append(nb, b[end:]...), fmode)
+}
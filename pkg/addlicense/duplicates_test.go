@@ -0,0 +1,49 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import "testing"
+
+func TestDuplicateLicense(t *testing.T) {
+	tests := []struct {
+		path      string
+		content   string
+		wantOK    bool
+		identical bool
+		fixed     string // content without the redundant header
+	}{
+		{"f.go", "// Copyright 2020 Acme\n\n// Copyright 2020 Acme\n\npackage main\n", true, true, "// Copyright 2020 Acme\n\npackage main\n"},
+		{"f.sh", "#!/bin/sh\n# Copyright 2020 Acme\n# MIT\n\n#  Copyright 2020 Acme\n#  MIT\necho\n", true, true, "#!/bin/sh\n# Copyright 2020 Acme\n# MIT\necho\n"},
+		{"f.c", "/*\n * Copyright 2020 Acme\n */\n/*\n * Copyright 2020 Acme\n */\n\nint x;\n", true, true, "/*\n * Copyright 2020 Acme\n */\n\nint x;\n"},
+		{"f.go", "// Copyright 2020 Acme\n\n// Copyright 2019 Other\n\npackage main\n", true, false, ""},
+
+		{"f.go", "// Copyright 2020 Acme\n\n// Package main does things.\npackage main\n", false, false, ""},
+		{"f.go", "// Copyright 2020 Acme\n\npackage main\n\n// Copyright 2020 Acme\n", false, false, ""},
+		{"f.go", "package main\n", false, false, ""},
+	}
+	for _, tt := range tests {
+		b := []byte(tt.content)
+		start, end, identical, ok := duplicateLicense(fileCommentStyle(tt.path), tt.path, b, licenseMarkers)
+		if ok != tt.wantOK || identical != tt.identical {
+			t.Errorf("duplicateLicense(%q, %q) returned identical %v, ok %v, want %v, %v", tt.path, tt.content, identical, ok, tt.identical, tt.wantOK)
+			continue
+		}
+		if identical {
+			if got := string(b[:start]) + string(b[end:]); got != tt.fixed {
+				t.Errorf("duplicateLicense(%q, %q) fixed contents are %q, want %q", tt.path, tt.content, got, tt.fixed)
+			}
+		}
+	}
+}
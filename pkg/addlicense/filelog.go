@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// logger is implemented by *log.Logger and *fileLog.
+type logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// fileLog buffers the log lines about a file, so that they are written as one
+// block once the file is processed instead of interleaved with the lines about
+// the files processed concurrently.
+type fileLog struct {
+	lines []string
+}
+
+// Printf buffers a log line.
+func (l *fileLog) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+}
+
+// style replaces the buffered lines with their formatting by fn. The lines
+// are copied, since they may be shared with the cache.
+func (l *fileLog) style(fn func(line string) string) {
+	lines := make([]string, len(l.lines))
+	for i, line := range l.lines {
+		lines[i] = fn(line)
+	}
+	l.lines = lines
+}
+
+// flush writes the buffered lines to out as one block, each but the first
+// line indented, and resets the buffer.
+func (l *fileLog) flush(out *log.Logger) {
+	if len(l.lines) == 0 {
+		return
+	}
+	out.Print(strings.Join(l.lines, "\n\t"))
+	l.lines = nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileLog(t *testing.T) {
+	var out strings.Builder
+	logger := log.New(&out, "", 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := &fileLog{}
+			for j := 0; j < 3; j++ {
+				l.Printf("file%d: line %d\n", i, j)
+			}
+			l.flush(logger)
+		}(i)
+	}
+	wg.Wait()
+
+	blocks := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(blocks) != 60 {
+		t.Fatalf("got %d lines, want 60:\n%s", len(blocks), out.String())
+	}
+	for i := 0; i < len(blocks); i += 3 {
+		var n int
+		if _, err := fmt.Sscanf(blocks[i], "file%d: line 0", &n); err != nil {
+			t.Fatalf("line %d is %q, want the first line of a block", i, blocks[i])
+		}
+		for j := 1; j < 3; j++ {
+			if want := fmt.Sprintf("\tfile%d: line %d", n, j); blocks[i+j] != want {
+				t.Errorf("line %d is %q, want %q", i+j, blocks[i+j], want)
+			}
+		}
+	}
+}
+
+func TestLogStyle(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"ok.go":  "// Copyright 2020 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n",
+		"gpl.go": "// Copyright 2020 Acme\n// SPDX-License-Identifier: GPL-2.0\n\npackage a\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out strings.Builder
+	Run(context.Background(), Options{
+		Roots:     []string{dir},
+		License:   "mit",
+		CheckOnly: true,
+		Forbid:    []string{"GPL"},
+		Verbose:   true,
+		Logger:    log.New(&out, "", 0),
+		LogStyle:  func(status Status, line string) string { return "[" + string(status) + "] " + line },
+	})
+	got := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(got)
+	want := []string{
+		"[forbidden] " + filepath.Join(dir, "gpl.go") + ": license header of forbidden license GPL-2.0",
+		"[ok] " + filepath.Join(dir, "ok.go") + " ok",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("logged %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// ErrMissingFooter is the error of files missing a required license footer in
+// check only mode.
+var ErrMissingFooter = errors.New("missing license footer")
+
+// parseFooters parses the footer templates of footers, which maps file
+// extensions without dot, or "*" for all files, to template texts.
+func parseFooters(footers map[string]string) (map[string]*template.Template, error) {
+	tmpls := make(map[string]*template.Template, len(footers))
+	for ext, text := range footers {
+		t, err := template.New(ext).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		tmpls[strings.ToLower(strings.TrimPrefix(ext, "."))] = t
+	}
+	return tmpls, nil
+}
+
+// footer returns the license footer required at the end of the file at path,
+// in its comment style, or nil if none is.
+func (r *runner) footer(path string) ([]byte, error) {
+	style := r.style(path)
+	if style == nil || len(r.footers) == 0 {
+		return nil, nil
+	}
+	ext := extKey(path)
+	t, ok := r.footers[ext]
+	if !ok {
+		if t, ok = r.footers["*"]; !ok {
+			return nil, nil
+		}
+	}
+	_, data, _ := r.license(path)
+	b, err := executeTemplate(t, data, style.top, style.mid, style.bot)
+	if err != nil {
+		return nil, err
+	}
+	// drop the blank line that separates headers from the code below them
+	return bytes.TrimSuffix(b, []byte("\n")), nil
+}
+
+// hasFooter reports whether b ends with footer, regardless of whitespace and
+// copyright years.
+func hasFooter(b, footer []byte) bool {
+	normalize := func(b []byte) []byte {
+		return copyrightYears.ReplaceAll(normalizeSpace(b), []byte("${1}YEAR"))
+	}
+	return bytes.HasSuffix(normalize(b), normalize(footer))
+}
+
+// addFooter appends the required license footer to the file at path, if
+// missing, separated from the rest of the file by a blank line.
+//
+// It returns true if the file was updated.
+func (r *runner) addFooter(path string, fmode os.FileMode) (bool, error) {
+	footer, err := r.footer(path)
+	if err != nil || footer == nil {
+		return false, err
+	}
+	b, err := r.readFile(path)
+	if err != nil {
+		return false, err
+	}
+	if isGenerated(b) || hasFooter(b, footer) {
+		return false, nil
+	}
+	nb := append([]byte(nil), b...)
+	if len(nb) > 0 {
+		if nb[len(nb)-1] != '\n' {
+			nb = append(nb, '\n')
+		}
+		nb = append(nb, '\n')
+	}
+	return true, r.writeFile(path, append(nb, footer...), fmode)
+}
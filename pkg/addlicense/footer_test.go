@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddFooter(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	footers, err := parseFooters(map[string]string{".c": "Copyright {{.Year}} {{.Holder}}\nEND OF FILE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &runner{
+		log:     log.New(ioutil.Discard, "", 0),
+		data:    LicenseData{Holder: "Acme", Year: "2020"},
+		footers: footers,
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"f.c", "int x;\n", "int x;\n\n/*\n * Copyright 2020 Acme\n * END OF FILE\n */\n"},
+		{"f.c", "int x;", "int x;\n\n/*\n * Copyright 2020 Acme\n * END OF FILE\n */\n"},
+		// footers are recognized regardless of whitespace and years
+		{"f.c", "int x;\n/*\n *  Copyright 2018-2019  Acme\n *  END OF FILE\n */\n\n", "int x;\n/*\n *  Copyright 2018-2019  Acme\n *  END OF FILE\n */\n\n"},
+		// no footer is required for other extensions
+		{"f.go", "package main\n", "package main\n"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modified, err := r.addFooter(path, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != tt.want || modified != (tt.content != tt.want) {
+			t.Errorf("addFooter of %s %q returned %v, wrote %q, want %q", tt.name, tt.content, modified, got, tt.want)
+		}
+	}
+}
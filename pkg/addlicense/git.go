@@ -0,0 +1,113 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitStagedFiles returns the files staged in the git index that match
+// pathspecs, relative to the current directory. If addedOnly is true, only
+// newly added files are returned, leaving out modified ones.
+func gitStagedFiles(addedOnly bool, pathspecs []string) ([]string, error) {
+	filter := "ACMR"
+	if addedOnly {
+		filter = "A"
+	}
+	args := []string{"diff", "--cached", "--name-only", "--relative", "-z", "--diff-filter=" + filter, "--"}
+	return gitFiles(append(args, pathspecs...)...)
+}
+
+// gitTrackedFiles returns the files tracked by git that match pathspecs,
+// relative to the current directory. Tracked files deleted from the working
+// tree are left out.
+func gitTrackedFiles(pathspecs []string) ([]string, error) {
+	files, err := gitFiles(append([]string{"ls-files", "-z", "--"}, pathspecs...)...)
+	if err != nil {
+		return nil, err
+	}
+	existing := files[:0]
+	for _, f := range files {
+		if _, err := os.Lstat(f); err == nil {
+			existing = append(existing, f)
+		}
+	}
+	return existing, nil
+}
+
+// gitChangedFiles returns the files that match pathspecs added or modified
+// since the merge base of ref and HEAD, including uncommitted changes,
+// relative to the current directory.
+func gitChangedFiles(ref string, pathspecs []string) ([]string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "merge-base", ref, "HEAD")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git merge-base: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	base := strings.TrimSpace(string(out))
+	args := []string{"diff", "--name-only", "--relative", "-z", "--diff-filter=ACMR", base, "--"}
+	return gitFiles(append(args, pathspecs...)...)
+}
+
+// gitFiles runs git with args and returns the NUL separated file names it
+// prints.
+func gitFiles(args ...string) ([]string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	var files []string
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// gitYears returns the copyright years of the file at path according to its
+// git history, following renames: the year of its first commit, or the range
+// from that year to the year of its last commit. It returns an empty string
+// if the file has no history.
+func gitYears(path string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "log", "--follow", "--format=%ad", "--date=format:%Y", "--", filepath.Base(path))
+	cmd.Dir = filepath.Dir(path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git log: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	years := strings.Fields(string(out))
+	if len(years) == 0 {
+		return "", nil
+	}
+	// commits are listed newest first
+	first, last := years[len(years)-1], years[0]
+	if first == last {
+		return first, nil
+	}
+	return first + "-" + last, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testRepo creates a git repository in a temporary directory, and returns it
+// with a function committing a file at a date.
+func testRepo(t *testing.T) (dir string, commit func(name, content, date string)) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir = tempDir(t)
+	git := func(date string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=a", "GIT_AUTHOR_EMAIL=a@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=a", "GIT_COMMITTER_EMAIL=a@example.com", "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git("", "init", "-q")
+	return dir, func(name, content, date string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git(date, "add", name)
+		git(date, "commit", "-q", "-m", name)
+	}
+}
+
+func TestGitYears(t *testing.T) {
+	dir, commit := testRepo(t)
+	defer os.RemoveAll(dir)
+	commit("old.go", "package a\n", "2016-05-01T12:00:00Z")
+	commit("old.go", "package a\n\nvar x int\n", "2023-05-01T12:00:00Z")
+	commit("new.go", "package a\n", "2024-05-01T12:00:00Z")
+	if err := ioutil.WriteFile(filepath.Join(dir, "untracked.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"old.go":       "2016-2023",
+		"new.go":       "2024",
+		"untracked.go": "",
+	} {
+		got, err := gitYears(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("gitYears(%s) returned %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGitTrackedFiles(t *testing.T) {
+	dir, commit := testRepo(t)
+	defer os.RemoveAll(dir)
+	commit("a.go", "package a\n", "2020-01-01T12:00:00Z")
+	commit("deleted.go", "package a\n", "2020-01-01T12:00:00Z")
+	if err := os.Remove(filepath.Join(dir, "deleted.go")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "untracked.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	got, err := gitTrackedFiles(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("gitTrackedFiles returned %q, want %q", got, want)
+	}
+}
+
+func TestGitChangedFiles(t *testing.T) {
+	dir, commit := testRepo(t)
+	defer os.RemoveAll(dir)
+	commit("old.go", "package a\n", "2020-01-01T12:00:00Z")
+	commit("changed.go", "package a\n", "2020-01-01T12:00:00Z")
+	cmd := exec.Command("git", "tag", "base")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git tag: %v: %s", err, out)
+	}
+	commit("new.go", "package a\n", "2021-01-01T12:00:00Z")
+	if err := ioutil.WriteFile(filepath.Join(dir, "changed.go"), []byte("package a\n\nvar x int\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	got, err := gitChangedFiles("base", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"changed.go", "new.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("gitChangedFiles returned %q, want %q", got, want)
+	}
+	if _, err := gitChangedFiles("no-such-ref", nil); err == nil {
+		t.Error("gitChangedFiles of an unknown ref returned no error")
+	}
+}
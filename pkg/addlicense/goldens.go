@@ -0,0 +1,71 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// goldenFile returns the path of the golden file of style in dir.
+func goldenFile(dir string, style *commentStyle) string {
+	return filepath.Join(dir, style.name+".golden")
+}
+
+// CompareGoldens renders tmpl with data in every comment style and writes to w
+// a unified diff of each rendered header that differs from its golden file in
+// dir. A missing golden file is treated as empty. It returns the number of
+// differing styles.
+func CompareGoldens(w io.Writer, dir string, tmpl *template.Template, data LicenseData) (int, error) {
+	n := 0
+	for _, style := range commentStyles {
+		got, err := executeTemplate(tmpl, data, style.top, style.mid, style.bot)
+		if err != nil {
+			return n, err
+		}
+		path := goldenFile(dir, style)
+		want, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return n, err
+		}
+		if d := unifiedDiff(path, style.name, want, got); d != "" {
+			fmt.Fprint(w, d)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// UpdateGoldens renders tmpl with data in every comment style and writes the
+// results to the golden files in dir.
+func UpdateGoldens(dir string, tmpl *template.Template, data LicenseData) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, style := range commentStyles {
+		b, err := executeTemplate(tmpl, data, style.top, style.mid, style.bot)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(goldenFile(dir, style), b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
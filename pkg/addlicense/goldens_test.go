@@ -0,0 +1,62 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestGoldens(t *testing.T) {
+	dir := tempDir(t)
+	data := LicenseData{Holder: "H", Year: "Y"}
+	tmpl := template.Must(template.New("").Parse("Copyright {{.Year}} {{.Holder}}"))
+
+	var out strings.Builder
+	n, err := CompareGoldens(&out, dir, tmpl, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(commentStyles) {
+		t.Errorf("compareGoldens without golden files returned %d differences, want %d", n, len(commentStyles))
+	}
+
+	if err := UpdateGoldens(dir, tmpl, data); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(goldenFile(dir, styleHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "# Copyright Y H\n\n"; got != want {
+		t.Errorf("golden file of hash style contains %q, want %q", got, want)
+	}
+
+	out.Reset()
+	if n, err := CompareGoldens(&out, dir, tmpl, data); err != nil || n != 0 {
+		t.Errorf("compareGoldens after update returned %d, %v, want no differences\n%s", n, err, out.String())
+	}
+
+	tmpl = template.Must(template.New("").Parse("Copyright {{.Year}} {{.Holder}} All rights reserved."))
+	out.Reset()
+	if n, err := CompareGoldens(&out, dir, tmpl, data); err != nil || n != len(commentStyles) {
+		t.Errorf("compareGoldens with changed template returned %d, %v, want %d differences", n, err, len(commentStyles))
+	}
+	if !strings.Contains(out.String(), "+# Copyright Y H All rights reserved.\n") {
+		t.Errorf("compareGoldens output doesn't show the changed hash header:\n%s", out.String())
+	}
+}
@@ -0,0 +1,636 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode/utf8"
+)
+
+// licenseHeader populates the provided license template with data, and returns
+// it as a comment in style, or nil if style is nil.
+func licenseHeader(style *commentStyle, tmpl *template.Template, data LicenseData) ([]byte, error) {
+	if style == nil {
+		return nil, nil
+	}
+	return executeTemplate(tmpl, data, style.top, style.mid, style.bot)
+}
+
+// headerKey identifies a license header rendered by a headerCache.
+type headerKey struct {
+	style *commentStyle
+	tmpl  *template.Template
+	data  string
+}
+
+// headerCache caches rendered license headers: they only depend on the
+// comment style, template and data, which are shared by most files of a run.
+type headerCache struct {
+	mu      sync.Mutex
+	headers map[headerKey][]byte
+}
+
+// header returns the license header of licenseHeader, rendering it only once
+// for each style, template and data. The returned slice can be modified.
+func (c *headerCache) header(style *commentStyle, tmpl *template.Template, data LicenseData) ([]byte, error) {
+	if style == nil {
+		return nil, nil
+	}
+	key := headerKey{style, tmpl, strings.Join(append([]string{data.Year, data.Holder, data.SPDXID, data.FileType}, data.FileContributors...), "\x00")}
+	c.mu.Lock()
+	lic, ok := c.headers[key]
+	c.mu.Unlock()
+	if !ok {
+		var err error
+		if lic, err = licenseHeader(style, tmpl, data); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.headers == nil {
+			c.headers = make(map[headerKey][]byte)
+		}
+		c.headers[key] = lic
+		c.mu.Unlock()
+	}
+	return append([]byte(nil), lic...), nil
+}
+
+// commentStyle describes how a license header is turned into a comment: top
+// and bot are the lines opening and closing a block comment, if any, and mid
+// prefixes each line of the license text.
+type commentStyle struct {
+	name          string
+	top, mid, bot string
+}
+
+var (
+	styleC       = &commentStyle{"c", "/*", " * ", " */"}
+	styleJSDoc   = &commentStyle{"jsdoc", "/**", " * ", " */"}
+	styleSlash   = &commentStyle{"slash", "", "// ", ""}
+	styleHash    = &commentStyle{"hash", "", "# ", ""}
+	styleLisp    = &commentStyle{"lisp", "", ";; ", ""}
+	stylePercent = &commentStyle{"percent", "", "% ", ""}
+	styleDash    = &commentStyle{"dash", "", "-- ", ""}
+	styleHTML    = &commentStyle{"html", "<!--", " ", "-->"}
+	styleJinja   = &commentStyle{"jinja", "{#", "", "#}"}
+	styleOCaml   = &commentStyle{"ocaml", "(**", "   ", "*)"}
+	styleRem     = &commentStyle{"rem", "", "REM ", ""}
+	styleColons  = &commentStyle{"colons", "", ":: ", ""}
+	styleSemi    = &commentStyle{"semicolon", "", "; ", ""}
+	styleRST     = &commentStyle{"rst", "", ".. ", ""}
+	styleBang    = &commentStyle{"bang", "", "! ", ""}
+	stylePascal  = &commentStyle{"pascal", "{", "  ", "}"}
+	styleVB      = &commentStyle{"vb", "", "' ", ""}
+)
+
+// commentStyles lists all comment styles.
+var commentStyles = []*commentStyle{
+	styleC, styleJSDoc, styleSlash, styleHash, styleLisp,
+	stylePercent, styleDash, styleHTML, styleJinja, styleOCaml,
+	styleRem, styleColons, styleSemi, styleRST, styleBang,
+	stylePascal, styleVB,
+}
+
+// builtinStyles is the registry of the built-in comment styles of file
+// types, keyed by lowercase extension, such as ".go", possibly compound, such
+// as ".cmake.in", or by lowercase file name, such as "dockerfile". Runs add
+// to it and override it with Options.ExtStyles and Options.CommentStyles.
+var builtinStyles = map[string]*commentStyle{
+	".c":     styleC,
+	".h":     styleC,
+	".gv":    styleC,
+	".java":  styleC,
+	".scala": styleC,
+	".kt":    styleC,
+	".kts":   styleC,
+
+	".js":   styleJSDoc,
+	".mjs":  styleJSDoc,
+	".cjs":  styleJSDoc,
+	".jsx":  styleJSDoc,
+	".tsx":  styleJSDoc,
+	".css":  styleJSDoc,
+	".scss": styleJSDoc,
+	".sass": styleJSDoc,
+	".ts":   styleJSDoc,
+
+	".cc":     styleSlash,
+	".cpp":    styleSlash,
+	".cs":     styleSlash,
+	".go":     styleSlash,
+	".hcl":    styleSlash,
+	".hh":     styleSlash,
+	".hpp":    styleSlash,
+	".m":      styleSlash,
+	".mm":     styleSlash,
+	".proto":  styleSlash,
+	".rs":     styleSlash,
+	".swift":  styleSlash,
+	".dart":   styleSlash,
+	".groovy": styleSlash,
+	".v":      styleSlash,
+	".sv":     styleSlash,
+	".adoc":   styleSlash,
+	".php":    styleSlash,
+
+	".py":            styleHash,
+	".sh":            styleHash,
+	".yaml":          styleHash,
+	".yml":           styleHash,
+	".dockerfile":    styleHash,
+	"dockerfile":     styleHash,
+	".rb":            styleHash,
+	"gemfile":        styleHash,
+	".tcl":           styleHash,
+	".tf":            styleHash,
+	".tofu":          styleHash,
+	".bzl":           styleHash,
+	".pl":            styleHash,
+	".pm":            styleHash,
+	".t":             styleHash,
+	".raku":          styleHash,
+	".rakumod":       styleHash,
+	".pp":            styleHash,
+	"build":          styleHash,
+	".build":         styleHash,
+	".toml":          styleHash,
+	".cfg":           styleHash,
+	".properties":    styleHash,
+	".org":           styleHash,
+	"cmakelists.txt": styleHash,
+	".cmake":         styleHash,
+	".cmake.in":      styleHash,
+	"makefile":       styleHash,
+	"gnumakefile":    styleHash,
+	".mk":            styleHash,
+	".r":             styleHash,
+	".jl":            styleHash,
+	".ps1":           styleHash,
+	".psm1":          styleHash,
+	".psd1":          styleHash,
+	".s":             styleHash,
+
+	".el":   styleLisp,
+	".lisp": styleLisp,
+
+	".erl": stylePercent,
+	".tex": stylePercent,
+	".sty": stylePercent,
+	".cls": stylePercent,
+	".bib": stylePercent,
+
+	".ini": styleSemi,
+	".asm": styleSemi,
+
+	".rst": styleRST,
+
+	".f":   styleBang,
+	".f90": styleBang,
+	".f95": styleBang,
+	".f03": styleBang,
+
+	".pas": stylePascal,
+	".dpr": stylePascal,
+	".lpr": stylePascal,
+
+	".vb":  styleVB,
+	".vbs": styleVB,
+	".bas": styleVB,
+
+	".hs":  styleDash,
+	".sql": styleDash,
+	".sdl": styleDash,
+	".lua": styleDash,
+	".ads": styleDash,
+	".adb": styleDash,
+
+	".html": styleHTML,
+	".xml":  styleHTML,
+	".vue":  styleHTML,
+	".wxi":  styleHTML,
+	".wxl":  styleHTML,
+	".wxs":  styleHTML,
+
+	".md":       styleHTML,
+	".markdown": styleHTML,
+
+	".j2": styleJinja,
+
+	".ml":  styleOCaml,
+	".mli": styleOCaml,
+	".mll": styleOCaml,
+	".mly": styleOCaml,
+
+	// "::" labels break parenthesized blocks, REM is safe anywhere
+	".bat": styleRem,
+	".cmd": styleRem,
+}
+
+// fileCommentStyle returns the built-in comment style for the file type
+// specified by path, or nil if the file type is unknown. The file name is
+// looked up in builtinStyles first, then its extensions, from the longest
+// compound one to the last one.
+func fileCommentStyle(path string) *commentStyle {
+	base := strings.ToLower(filepath.Base(path))
+	if s, ok := builtinStyles[base]; ok {
+		return s
+	}
+	for i := 1; i < len(base); i++ {
+		if base[i] != '.' {
+			continue
+		}
+		if s, ok := builtinStyles[base[i:]]; ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// fileExtension returns the file extension of name, or the full name if there
+// is no extension.
+func fileExtension(name string) string {
+	if v := filepath.Ext(name); v != "" {
+		return v
+	}
+	return name
+}
+
+var head = []string{
+	"#!",                       // shell script
+	"<?xml",                    // XML declaratioon
+	"<!doctype",                // HTML doctype
+	"# encoding:",              // Ruby encoding
+	"# frozen_string_literal:", // Ruby interpreter instruction
+	"<?php",                    // PHP opening tag
+	"# escape",                 // Dockerfile directive https://docs.docker.com/engine/reference/builder/#parser-directives
+	"# syntax",                 // Dockerfile directive https://docs.docker.com/engine/reference/builder/#parser-directives
+	"@echo off",                // Windows batch file command echoing
+	"attribute vb_name",        // Visual Basic 6 module name
+}
+
+// maxHeadLine is the maximum length of a hashbang line or similar preamble.
+// Longer first lines, such as those of minified files, are not scanned
+// further.
+const maxHeadLine = 4096
+
+// hashBang returns a copy of the first line of b, including its newline if
+// any, if it is a hashbang line or similar preamble that must stay on top of
+// the file, or nil otherwise.
+func hashBang(b []byte) []byte {
+	n := len(b)
+	if n > maxHeadLine {
+		n = maxHeadLine
+	}
+	end := bytes.IndexByte(b[:n], '\n') + 1
+	if end == 0 {
+		if len(b) > maxHeadLine {
+			return nil
+		}
+		end = len(b)
+	}
+	first := bytes.ToLower(headWindow(b[:end], 32))
+	for _, h := range head {
+		if bytes.HasPrefix(first, []byte(h)) {
+			return append([]byte(nil), b[:end]...)
+		}
+	}
+	return nil
+}
+
+// isPercentScript reports whether path may hold a Jupytext "py:percent"
+// notebook, in which cells are delimited by "# %%" markers.
+func isPercentScript(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".py"
+}
+
+// percentPreamble returns the Jupytext metadata header of b, a py:percent
+// notebook, and the blank lines following it, which stay above the license
+// header. Other comments preceding the first cell are left below it, where
+// licenseBlock doesn't mistake them for part of the header.
+func percentPreamble(b []byte) []byte {
+	off := len(jupytextHeader(b))
+	if off == 0 {
+		return nil
+	}
+	for off < len(b) {
+		line, next := nextLine(b, off)
+		if len(bytes.TrimSpace(line)) > 0 {
+			break
+		}
+		off = next
+	}
+	return b[:off]
+}
+
+// objcLine and matlabLine match lines that only start Objective-C, and only
+// start MATLAB or Octave code, respectively.
+var (
+	objcLine   = regexp.MustCompile(`(?m)^[ \t]*(#[ \t]*(import|include|define|if|pragma)\b|@(interface|implementation|protocol|end)\b|//|/\*)`)
+	matlabLine = regexp.MustCompile(`(?m)^[ \t]*(%|function\b|classdef\b|end[ \t]*;?[ \t]*$)`)
+)
+
+// isMATLAB reports whether b, the contents of a .m file or their start, holds
+// MATLAB or Octave code rather than Objective-C: some of its lines only start
+// MATLAB code, such as % comments and function definitions, and none only
+// start Objective-C code, such as #import directives and // comments.
+func isMATLAB(b []byte) bool {
+	b = headWindow(b, binaryBlock)
+	return matlabLine.Match(b) && !objcLine.Match(b)
+}
+
+// pascalLine and puppetLine match lines that only start Pascal, and only
+// start Puppet manifests, respectively.
+var (
+	pascalLine = regexp.MustCompile(`(?im)^[ \t]*(unit|program|library|uses|interface|implementation|begin)\b|^[ \t]*\{\$`)
+	puppetLine = regexp.MustCompile(`(?m)^[ \t]*(#|(class|define)[ \t]+[\w:]+[ \t]*(\{|\(|inherits\b)|node[ \t]+('|"|/|default\b))`)
+)
+
+// isPascal reports whether b, the contents of a .pp file or their start,
+// holds Free Pascal code rather than a Puppet manifest: some of its lines
+// only start Pascal code, such as unit and program declarations, and none
+// only start Puppet manifests, such as # comments and class definitions.
+func isPascal(b []byte) bool {
+	b = headWindow(b, binaryBlock)
+	return pascalLine.Match(b) && !puppetLine.Match(b)
+}
+
+// go generate: ^// Code generated .* DO NOT EDIT\.$
+var goGenerated = regexp.MustCompile(`(?m)^.{1,2} Code generated .* DO NOT EDIT\.$`)
+
+// cargo raze: ^DO NOT EDIT! Replaced on runs of cargo-raze$
+var cargoRazeGenerated = regexp.MustCompile(`(?m)^DO NOT EDIT! Replaced on runs of cargo-raze$`)
+
+// terraform lock file: ^# This file is maintained automatically by "terraform init".$
+var terraformLockGenerated = regexp.MustCompile(`(?m)^# This file is maintained automatically by "(terraform|tofu) init"\.$`)
+
+// isGenerated returns true if it contains a string that implies the file was
+// generated.
+func isGenerated(b []byte) bool {
+	return goGenerated.Match(b) || cargoRazeGenerated.Match(b) || terraformLockGenerated.Match(b)
+}
+
+// binaryBlock is the number of bytes at the start of files inspected to tell
+// binary files apart, as git does.
+const binaryBlock = 8000
+
+// isBinary reports whether b, the contents of a file, holds binary data: a NUL
+// byte or invalid UTF-8 in its first binaryBlock bytes. Such files may have a
+// known extension, or none, and still not take comments.
+func isBinary(b []byte) bool {
+	head := headWindow(b, binaryBlock)
+	return bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(head)
+}
+
+// minifiedLineLength is the mean line length above which JavaScript and CSS
+// files are deemed minified, as linguist does.
+const minifiedLineLength = 110
+
+// isMinified reports whether the file at path, of contents b or their start,
+// is minified JavaScript or CSS: its name says so, such as "app.min.js", or
+// the mean length of its lines exceeds minifiedLineLength.
+func isMinified(path string, b []byte) bool {
+	name := strings.ToLower(filepath.Base(path))
+	ext := filepath.Ext(name)
+	switch ext {
+	case ".js", ".mjs", ".cjs", ".css":
+	default:
+		return false
+	}
+	base := strings.TrimSuffix(name, ext)
+	if strings.HasSuffix(base, ".min") || strings.HasSuffix(base, "-min") {
+		return true
+	}
+	b = bytes.TrimRight(b, "\n")
+	if len(b) == 0 {
+		return false
+	}
+	lines := bytes.Count(b, []byte("\n")) + 1
+	return len(b)/lines > minifiedLineLength
+}
+
+// licenseMarkers are lowercase phrases whose presence near the top of a file
+// indicates that it already has a license header. Public domain dedications,
+// such as the Unlicense or CC0, don't necessarily mention a copyright.
+var licenseMarkers = [][]byte{
+	[]byte("copyright"),
+	[]byte("mozilla public"),
+	[]byte("spdx-license-identifier"),
+	[]byte("released into the public domain"),
+	[]byte("dedicated to the public domain"),
+	[]byte("public domain dedication"),
+}
+
+// hasLicense reports whether b contains one of the licenseMarkers in its first
+// 1000 bytes.
+func hasLicense(b []byte) bool {
+	return containsMarker(b, licenseMarkers)
+}
+
+// containsMarker reports whether b contains one of the lowercase markers in
+// its first 1000 bytes, ignoring case.
+func containsMarker(b []byte, markers [][]byte) bool {
+	lower := strings.ToLower(string(headWindow(b, 1000)))
+	for _, m := range markers {
+		if strings.Contains(lower, string(m)) {
+			return true
+		}
+	}
+	return false
+}
+
+// headWindow returns at most the first n bytes of b, shortened so as not to
+// split a multibyte UTF-8 sequence.
+func headWindow(b []byte, n int) []byte {
+	if len(b) <= n {
+		return b
+	}
+	// back off to the start of the rune straddling the window end, if any
+	for i := n; i > 0 && i > n-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			return b[:i]
+		}
+	}
+	return b[:n]
+}
+
+// licenseBlock locates the existing license header of b, the contents of the
+// file at path. The header is the first comment block, in style, that follows
+// any hashbang line or similar preamble, up to the end of its license text if
+// it is a block of line comments directly followed by code. It returns the
+// offsets of the header, including the newline ending its last line, and false
+// if there is no such block or if it contains none of the license markers.
+func licenseBlock(style *commentStyle, path string, b []byte, markers [][]byte) (start, end int, ok bool) {
+	if style == nil {
+		return 0, 0, false
+	}
+	off := len(hashBang(b))
+	if isPercentScript(path) {
+		off += len(jupytextHeader(b[off:]))
+	}
+	if isMarkdown(path) {
+		off += len(frontMatter(b[off:]))
+	}
+	start, end, ok = commentBlock(style, b, off)
+	if !ok || !containsMarker(b[start:end], markers) {
+		return 0, 0, false
+	}
+	if strings.TrimSpace(style.top) == "" {
+		end = licenseTextEnd(style, b, start, end, markers)
+	}
+	return start, end, true
+}
+
+// licenseTextEnd returns the end of the license text of b[start:end], a block
+// of line comments in style. A block directly followed by code may end with
+// the doc comment of that code, as in Go files whose package comment follows
+// the copyright line: the license text then ends with the last line holding
+// a license marker or a line of a built-in license template, and the empty
+// comment lines following it. Other blocks are license text as a whole.
+func licenseTextEnd(style *commentStyle, b []byte, start, end int, markers [][]byte) int {
+	if line, _ := nextLine(b, end); end == len(b) || len(bytes.TrimSpace(line)) == 0 {
+		return end
+	}
+	mid := []byte(strings.TrimSpace(style.mid))
+	text := start
+	for off := start; off < end; {
+		var line []byte
+		line, off = nextLine(b, off)
+		line = bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(line), mid))
+		if containsMarker(line, markers) || templateLines[strings.ToLower(string(normalizeSpace(line)))] {
+			text = off
+		}
+	}
+	// the empty comment lines separating the doc comment go with the license
+	for text < end {
+		line, next := nextLine(b, text)
+		if !bytes.Equal(bytes.TrimSpace(line), mid) {
+			break
+		}
+		text = next
+	}
+	return text
+}
+
+// templateLines holds the lowercase lines of the built-in license templates,
+// except those filled out with license data.
+var templateLines = func() map[string]bool {
+	lines := make(map[string]bool)
+	for _, tmpl := range licenseTemplate {
+		for _, l := range strings.Split(tmpl, "\n") {
+			if l = strings.Join(strings.Fields(l), " "); l != "" && !strings.Contains(l, "{{") {
+				lines[strings.ToLower(l)] = true
+			}
+		}
+	}
+	return lines
+}()
+
+// commentBlock locates the comment block in style that follows the blank
+// lines starting at offset off of b. It returns the offsets of that block,
+// including the newline ending its last line, and false if there is none.
+func commentBlock(style *commentStyle, b []byte, off int) (start, end int, ok bool) {
+	// skip blank lines
+	for off < len(b) {
+		line, next := nextLine(b, off)
+		if len(bytes.TrimSpace(line)) > 0 {
+			break
+		}
+		off = next
+	}
+	start, end = off, off
+
+	top := []byte(strings.TrimSpace(style.top))
+	mid := []byte(strings.TrimSpace(style.mid))
+	bot := []byte(strings.TrimSpace(style.bot))
+	if len(top) > 0 {
+		line, next := nextLine(b, start)
+		line = bytes.TrimSpace(line)
+		if !bytes.HasPrefix(line, top) {
+			return 0, 0, false
+		}
+		// the block may be closed on its first line, as in "/* ... */"
+		end = next
+		closed := len(line) >= len(top)+len(bot) && bytes.HasSuffix(line, bot)
+		for !closed && end < len(b) {
+			line, end = nextLine(b, end)
+			closed = bytes.HasSuffix(bytes.TrimSpace(line), bot)
+		}
+		if !closed {
+			return 0, 0, false
+		}
+	} else {
+		for end < len(b) {
+			line, next := nextLine(b, end)
+			if !bytes.HasPrefix(bytes.TrimSpace(line), mid) {
+				break
+			}
+			end = next
+		}
+	}
+	return start, end, end > start
+}
+
+// jupytextHeader returns the Jupytext metadata header of a py:percent
+// notebook, delimited by "# ---" lines, if b starts with one.
+func jupytextHeader(b []byte) []byte {
+	return delimitedBlock(b, "# ---")
+}
+
+// isMarkdown reports whether path may hold a Markdown document, whose YAML
+// front matter must stay on top for static site generators to find it.
+func isMarkdown(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// frontMatter returns the YAML front matter of a Markdown document, delimited
+// by "---" lines, if b starts with one.
+func frontMatter(b []byte) []byte {
+	return delimitedBlock(b, "---")
+}
+
+// delimitedBlock returns the lines of b up to and including the second line
+// consisting of delim, if its first line does.
+func delimitedBlock(b []byte, delim string) []byte {
+	line, off := nextLine(b, 0)
+	if string(bytes.TrimSpace(line)) != delim {
+		return nil
+	}
+	for off < len(b) {
+		line, off = nextLine(b, off)
+		if string(bytes.TrimSpace(line)) == delim {
+			return b[:off]
+		}
+	}
+	return nil
+}
+
+// nextLine returns the line of b starting at off, including its newline if
+// any, and the offset of the following line.
+func nextLine(b []byte, off int) ([]byte, int) {
+	end := bytes.IndexByte(b[off:], '\n')
+	if end < 0 {
+		return b[off:], len(b)
+	}
+	return b[off : off+end+1], off + end + 1
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+	"unicode/utf8"
+)
+
+func TestLicenseBlock(t *testing.T) {
+	tests := []struct {
+		path    string
+		content string
+		want    string // expected header, empty if none
+	}{
+		{"f.go", "// Copyright 2018 Google LLC\n//\n// Licensed.\n\npackage main\n", "// Copyright 2018 Google LLC\n//\n// Licensed.\n"},
+		{"f.go", "\n\n// Copyright 2018 Google LLC\npackage main\n", "// Copyright 2018 Google LLC\n"},
+		{"f.go", "// Copyright 2018 Google LLC", "// Copyright 2018 Google LLC"},
+		{"f.c", "/*\n * Copyright 2018 Google LLC\n */\n\nint x;\n", "/*\n * Copyright 2018 Google LLC\n */\n"},
+		{"f.c", "/* Copyright 2018 Google LLC */\nint x;\n", "/* Copyright 2018 Google LLC */\n"},
+		{"f.js", "/**\n * Copyright 2018 Google LLC\n */\n", "/**\n * Copyright 2018 Google LLC\n */\n"},
+		{"f.sh", "#!/bin/sh\n# Copyright 2018 Google LLC\n\necho\n", "# Copyright 2018 Google LLC\n"},
+		{"f.html", "<!doctype html>\n<!--\n Copyright 2018 Google LLC\n-->\n<html>\n", "<!--\n Copyright 2018 Google LLC\n-->\n"},
+		{"f.py", "# ---\n# jupyter:\n# ---\n\n# Copyright 2018 Google LLC\n\n# %%\n", "# Copyright 2018 Google LLC\n"},
+
+		// no header, unknown file type, or comment that isn't a license
+		{"f.go", "package main\n", ""},
+		{"f.go", "// Package main does things.\npackage main\n", ""},
+		{"f.c", "/*\n * Copyright 2018 Google LLC\n", ""},
+		{"f.unknown", "// Copyright 2018 Google LLC\n", ""},
+	}
+
+	for _, tt := range tests {
+		var got string
+		if start, end, ok := licenseBlock(fileCommentStyle(tt.path), tt.path, []byte(tt.content), licenseMarkers); ok {
+			got = tt.content[start:end]
+		}
+		if got != tt.want {
+			t.Errorf("licenseBlock(%q, %q) returned %q, want %q", tt.path, tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestFileCommentStyle(t *testing.T) {
+	tests := []struct {
+		path string
+		want *commentStyle
+	}{
+		{"a/main.go", styleSlash},
+		{"a/Dockerfile", styleHash},
+		{"a/CMakeLists.txt", styleHash},
+		{"a/config.cmake.in", styleHash},
+		{"a/GNUmakefile", styleHash},
+		{"a/rules.mk", styleHash},
+		{"a/jquery.min.js", styleJSDoc},
+		{"a/.eslintrc.js", styleJSDoc},
+		{"a/Cargo.TOML", styleHash},
+		{"a/main.go.bak", nil},
+		{"a/notes.txt", nil},
+		{"a/go", nil},
+		{"a/.go", styleSlash},
+	}
+	for _, tt := range tests {
+		if got := fileCommentStyle(tt.path); got != tt.want {
+			t.Errorf("fileCommentStyle(%q) returned %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestContainsMarker(t *testing.T) {
+	markers := [][]byte{[]byte("авторское право"), []byte("著作権"), []byte("copyright")}
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"// АВТОРСКОЕ ПРАВО 2020 ООО Ромашка\n", true},
+		{"// Авторское Право 2020 ООО Ромашка\n", true},
+		{"// 著作権 2020 株式会社サンプル\n", true},
+		{"// Ⓒ 2020 株式会社サンプル\n", false},
+		{"// Лицензия MIT\n", false},
+
+		// markers past the window are ignored, even if the window ends
+		// within a multibyte sequence
+		{"//" + strings.Repeat("Ж", 499) + "\n// Copyright 2020 Acme\n", false},
+		{"//" + strings.Repeat("語", 330) + "\n// 著作権\n", false},
+		{"// 著作権\n//" + strings.Repeat("語", 400), true},
+	}
+	for _, tt := range tests {
+		if got := containsMarker([]byte(tt.content), markers); got != tt.want {
+			t.Errorf("containsMarker(%q) returned %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestHeadWindow(t *testing.T) {
+	tests := []struct {
+		content string
+		n       int
+		want    string
+	}{
+		{"abc", 10, "abc"},
+		{"abcdef", 3, "abc"},
+		{"aЖb", 2, "a"},
+		{"aЖb", 3, "aЖ"},
+		{"a語b", 2, "a"},
+		{"a語b", 3, "a"},
+		{"a語b", 4, "a語"},
+	}
+	for _, tt := range tests {
+		got := headWindow([]byte(tt.content), tt.n)
+		if string(got) != tt.want || !utf8.Valid(got) {
+			t.Errorf("headWindow(%q, %d) returned %q, want %q", tt.content, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestHashBang(t *testing.T) {
+	long := "#!/bin/sh " + strings.Repeat("x", maxHeadLine)
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"#!/bin/sh\necho\n", "#!/bin/sh\n"},
+		{"#!/bin/sh", "#!/bin/sh"},
+		{"#!/usr/bin/perl -w\nuse strict;\n", "#!/usr/bin/perl -w\n"},
+		{"use strict;\nuse warnings;\n", ""},
+		{"<?XML version=\"1.0\"?>\n<a/>\n", "<?XML version=\"1.0\"?>\n"},
+		{"package main\n", ""},
+		{"", ""},
+
+		// first lines longer than maxHeadLine are not preambles
+		{long, ""},
+		{long + "\necho\n", ""},
+		{"var a=1;" + strings.Repeat("x", 10*maxHeadLine), ""},
+	}
+	for _, tt := range tests {
+		if got := string(hashBang([]byte(tt.content))); got != tt.want {
+			t.Errorf("hashBang(%.40q) returned %.40q, want %.40q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestIsMATLAB(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"function y = f(x)\n  y = x;\nend\n", true},
+		{"classdef Point\nend\n", true},
+		{"% Plots the data.\nplot(x, y);\n", true},
+		{"x = 1;\n", false},
+		{"#import <Foundation/Foundation.h>\n", false},
+		{"// Copyright 2020 Acme\n\n@implementation View\n@end\n", false},
+		{"/*\n * Copyright 2020 Acme\n */\n% not MATLAB\n", false},
+		{"#include \"a.h\"\nint f(void) {\n  return 1 % 2;\n}\n", false},
+	}
+	for _, tt := range tests {
+		if got := isMATLAB([]byte(tt.content)); got != tt.want {
+			t.Errorf("isMATLAB(%q) returned %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestIsPascal(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"program Hello;\nbegin\n  WriteLn('Hello');\nend.\n", true},
+		{"{$mode objfpc}\nunit Shapes;\n\ninterface\n", true},
+		{"UNIT Shapes;\nINTERFACE\n", true},
+		{"unit Shapes;\n\nclass function TShape.Create: TShape;\n", true},
+		{"class apache {\n  package { 'httpd': ensure => installed }\n}\n", false},
+		{"# Installs nginx.\nclass nginx::install inherits nginx {\n}\n", false},
+		{"node 'web01' {\n  include nginx\n}\n", false},
+		{"define site($root) {\n}\n", false},
+		{"package { 'vim': }\n", false},
+	}
+	for _, tt := range tests {
+		if got := isPascal([]byte(tt.content)); got != tt.want {
+			t.Errorf("isPascal(%q) returned %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestFrontMatter(t *testing.T) {
+	const front = "---\ntitle: Guide\nlayout: page\n---\n"
+	tests := []struct {
+		path    string
+		content string
+		want    string
+	}{
+		{"docs/guide.md", front + "# Guide\n", front},
+		{"docs/guide.markdown", front + "# Guide\n", front},
+		{"docs/guide.md", "# Guide\n\n---\n", ""},
+		{"docs/guide.md", "---\ntitle: Guide\n", ""},
+		{"config.yaml", front, ""},
+	}
+	for _, tt := range tests {
+		if got := string(leadingLines(tt.path, []byte(tt.content))); got != tt.want {
+			t.Errorf("leadingLines(%q, %q) returned %q, want %q", tt.path, tt.content, got, tt.want)
+		}
+	}
+
+	b := []byte(front + "<!--\n Copyright 2020 Acme\n-->\n\n# Guide\n")
+	start, end, ok := licenseBlock(styleHTML, "guide.md", b, licenseMarkers)
+	if got, want := string(b[start:end]), "<!--\n Copyright 2020 Acme\n-->\n"; !ok || got != want {
+		t.Errorf("licenseBlock of a document with front matter returned %q, %v, want %q", got, ok, want)
+	}
+}
+
+func TestHeaderCache(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(tmplMIT))
+	var c headerCache
+	for _, data := range []LicenseData{
+		{Year: "2020", Holder: "Acme"},
+		{Year: "2020", Holder: "Acme", FileContributors: []string{"Bob"}},
+		{Year: "2021", Holder: "Acme"},
+	} {
+		for _, style := range []*commentStyle{styleC, styleHash, styleC} {
+			want, err := licenseHeader(style, tmpl, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.header(style, tmpl, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("header(%s, %+v) returned:\n%s\nwant:\n%s", style.name, data, got, want)
+			}
+			// callers may modify the header
+			got[0] = 'X'
+		}
+	}
+	if got, err := c.header(nil, tmpl, LicenseData{}); got != nil || err != nil {
+		t.Errorf("header of an unknown style returned %q, %v", got, err)
+	}
+}
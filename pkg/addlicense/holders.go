@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	doublestar "github.com/bmatcuk/doublestar/v4"
+)
+
+// HolderRule assigns a new copyright holder, and optionally new years, to the
+// files matching a pattern.
+type HolderRule struct {
+	Pattern string
+	Holder  string
+	Year    string // empty to keep the existing years
+}
+
+// ReadHolderRules reads holder rules from a CSV file with one
+// "pattern,holder[,year]" record per line. Lines starting with '#' are
+// ignored.
+func ReadHolderRules(path string) ([]HolderRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseHolderRules(f)
+}
+
+func parseHolderRules(r io.Reader) ([]HolderRule, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	var rules []HolderRule
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return rules, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) < 2 || len(rec) > 3 {
+			return nil, fmt.Errorf("record %q: expected pattern,holder[,year]", rec)
+		}
+		if !doublestar.ValidatePattern(rec[0]) {
+			return nil, fmt.Errorf("pattern %q is not valid", rec[0])
+		}
+		rule := HolderRule{Pattern: rec[0], Holder: rec[1]}
+		if len(rec) == 3 {
+			rule.Year = rec[2]
+		}
+		rules = append(rules, rule)
+	}
+}
+
+// matchHolderRule returns the first rule whose pattern matches path, or nil.
+func matchHolderRule(path string, rules []HolderRule) *HolderRule {
+	for i := range rules {
+		if fileMatches(path, []string{rules[i].Pattern}) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// copyrightStatement matches a copyright statement on its own line, capturing
+// the leading comment characters with "Copyright", "Copyright (c)" or the
+// "SPDX-FileCopyrightText:" tag, the
+// optional years, the holder and an optional trailing "All rights reserved.".
+// Prose that merely mentions copyright, such as "The above copyright notice",
+// does not match.
+var copyrightStatement = regexp.MustCompile(`(?im)^([^\pL\pN\n]*(?:spdx-filecopyrighttext:|copyright)(?:[ \t]*\(c\)|[ \t]*©)?)(?:[ \t]+(\d{4}(?:[ \t]*[,\-–][ \t]*\d{4})*))?,?[ \t]+(.*?)([ \t]+all rights reserved\.?)?[ \t]*$`)
+
+// rewriteHolder rewrites the copyright statements of the license header of the
+// file at path to name the holder and years of the first matching rule.
+//
+// It returns true if the file was updated.
+func (r *runner) rewriteHolder(path string, fmode os.FileMode, rules []HolderRule) (bool, error) {
+	rule := matchHolderRule(path, rules)
+	if rule == nil {
+		return false, nil
+	}
+	b, err := r.readFile(path)
+	if err != nil {
+		return false, err
+	}
+	nb := rewriteHolderIn(b, rule.Holder, rule.Year)
+	if string(nb) == string(b) {
+		return false, nil
+	}
+	return true, r.writeFile(path, nb, fmode)
+}
+
+// rewriteHolderIn returns b with the copyright statements found in its first
+// 1000 bytes rewritten to name holder. Existing years are replaced with year,
+// unless year is empty.
+func rewriteHolderIn(b []byte, holder, year string) []byte {
+	n := 1000
+	if len(b) < n {
+		n = len(b)
+	}
+	head := copyrightStatement.ReplaceAllFunc(b[:n], func(m []byte) []byte {
+		sub := copyrightStatement.FindSubmatch(m)
+		years := string(sub[2])
+		if year != "" {
+			years = year
+		}
+		var s strings.Builder
+		s.Write(sub[1])
+		if years != "" {
+			s.WriteString(" " + years)
+		}
+		s.WriteString(" " + holder)
+		s.Write(sub[4])
+		return []byte(s.String())
+	})
+	return append(head, b[n:]...)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHolderRules(t *testing.T) {
+	rules, err := parseHolderRules(strings.NewReader("# pattern,holder,year\nsdk/**,Acme Corp\nlegacy/**, \"Acme, Inc.\", 2010-2020\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HolderRule{
+		{"sdk/**", "Acme Corp", ""},
+		{"legacy/**", "Acme, Inc.", "2010-2020"},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("parseHolderRules returned %v, want %v", rules, want)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("parseHolderRules returned rule %v, want %v", rules[i], want[i])
+		}
+	}
+	if r := matchHolderRule("legacy/a/b.go", rules); r == nil || r.Holder != "Acme, Inc." {
+		t.Errorf("matchHolderRule(%q) returned %v, want rule for legacy/**", "legacy/a/b.go", r)
+	}
+	if r := matchHolderRule("main.go", rules); r != nil {
+		t.Errorf("matchHolderRule(%q) returned %v, want nil", "main.go", r)
+	}
+
+	for _, bad := range []string{"sdk/**\n", "a,b,c,d\n", "[,Acme\n"} {
+		if _, err := parseHolderRules(strings.NewReader(bad)); err == nil {
+			t.Errorf("parseHolderRules(%q) returned no error", bad)
+		}
+	}
+}
+
+func TestRewriteHolderIn(t *testing.T) {
+	tests := []struct {
+		content      string
+		holder, year string
+		want         string
+	}{
+		{"// Copyright 2018 Google LLC\n\npackage main\n", "Acme", "", "// Copyright 2018 Acme\n\npackage main\n"},
+		{"// Copyright 2018 Google LLC\n", "Acme", "2020", "// Copyright 2020 Acme\n"},
+		{"/*\n * Copyright (c) 2015-2017,2019 Google LLC All rights reserved.\n */\n", "Acme", "", "/*\n * Copyright (c) 2015-2017,2019 Acme All rights reserved.\n */\n"},
+		{"# Copyright The Kubernetes Authors.\n", "Acme", "", "# Copyright Acme\n"},
+		{"# Copyright The Kubernetes Authors.\n", "Acme", "2021", "# Copyright 2021 Acme\n"},
+		{"// SPDX-FileCopyrightText: 2018 Google LLC\n// SPDX-License-Identifier: Apache-2.0\n", "Acme", "", "// SPDX-FileCopyrightText: 2018 Acme\n// SPDX-License-Identifier: Apache-2.0\n"},
+
+		// prose mentioning copyright is left alone
+		{"// The above copyright notice shall be included.\n", "Acme", "", "// The above copyright notice shall be included.\n"},
+		{"package main\n", "Acme", "", "package main\n"},
+	}
+
+	for _, tt := range tests {
+		if got := string(rewriteHolderIn([]byte(tt.content), tt.holder, tt.year)); got != tt.want {
+			t.Errorf("rewriteHolderIn(%q, %q, %q) returned %q, want %q", tt.content, tt.holder, tt.year, got, tt.want)
+		}
+	}
+}
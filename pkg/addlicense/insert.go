@@ -0,0 +1,93 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// InsertRule places the license headers added to the files matching a
+// pattern after the first line matching a regular expression, for file
+// layouts that the built-in placement doesn't know about, such as C files
+// that must include config.h first.
+type InsertRule struct {
+	Pattern string
+	After   string // regular expression, in which ^ and $ match at line boundaries
+}
+
+// InsertRules returns the rules of insertAfter, which maps file patterns to
+// regular expressions, ordered so that the longest, most specific patterns
+// come first.
+func InsertRules(insertAfter map[string]string) []InsertRule {
+	rules := make([]InsertRule, 0, len(insertAfter))
+	for p, re := range insertAfter {
+		rules = append(rules, InsertRule{Pattern: p, After: re})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].Pattern) != len(rules[j].Pattern) {
+			return len(rules[i].Pattern) > len(rules[j].Pattern)
+		}
+		return rules[i].Pattern < rules[j].Pattern
+	})
+	return rules
+}
+
+// insertAnchor is an InsertRule with its regular expression compiled.
+type insertAnchor struct {
+	pattern string
+	after   *regexp.Regexp
+}
+
+// parseInsertRules compiles the regular expressions of rules.
+func parseInsertRules(rules []InsertRule) ([]insertAnchor, error) {
+	var anchors []insertAnchor
+	for _, rule := range rules {
+		if !doublestar.ValidatePattern(rule.Pattern) {
+			return nil, fmt.Errorf("insert pattern %q is not valid", rule.Pattern)
+		}
+		re, err := regexp.Compile("(?m)" + rule.After)
+		if err != nil {
+			return nil, fmt.Errorf("insert anchor of %q: %v", rule.Pattern, err)
+		}
+		anchors = append(anchors, insertAnchor{pattern: rule.Pattern, after: re})
+	}
+	return anchors, nil
+}
+
+// leadingLines returns the lines at the start of b, the contents of the file
+// at path, that must stay above its license header: those up to and
+// including the first line matching the anchor of the first insert rule
+// matching path, if any, or the ones found by leadingLines.
+func (r *runner) leadingLines(path string, b []byte) []byte {
+	for _, a := range r.anchors {
+		if !fileMatches(path, []string{a.pattern}) {
+			continue
+		}
+		if loc := a.after.FindIndex(b); loc != nil {
+			last := loc[0]
+			if loc[1] > loc[0] {
+				last = loc[1] - 1
+			}
+			_, end := nextLine(b, last)
+			return b[:end]
+		}
+		break
+	}
+	return leadingLines(path, b)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInsertRules(t *testing.T) {
+	got := InsertRules(map[string]string{
+		"**/*.c":         "^#include <config.h>$",
+		"src/lib/**/*.c": "^#include \"lib.h\"$",
+	})
+	want := []InsertRule{
+		{"src/lib/**/*.c", "^#include \"lib.h\"$"},
+		{"**/*.c", "^#include <config.h>$"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InsertRules returned %v, want %v", got, want)
+	}
+}
+
+func TestInsertAfter(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"a.c":  "#include <config.h>\n#include <stdio.h>\n",
+		"b.c":  "#include <stdio.h>\n",
+		"c.sh": "#!/bin/sh\n#include <config.h>\necho\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := Run(context.Background(), Options{
+		Roots:       []string{dir},
+		Holder:      "Acme",
+		Year:        "2026",
+		License:     "MIT",
+		SPDX:        SPDXOnly,
+		InsertAfter: InsertRules(map[string]string{"**/*.c": "^#include <config.h>$"}),
+		Logger:      log.New(ioutil.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"a.c":  "#include <config.h>\n/*\n * Copyright 2026 Acme\n * SPDX-License-Identifier: MIT\n */\n\n#include <stdio.h>\n",
+		"b.c":  "/*\n * Copyright 2026 Acme\n * SPDX-License-Identifier: MIT\n */\n\n#include <stdio.h>\n",
+		"c.sh": "#!/bin/sh\n# Copyright 2026 Acme\n# SPDX-License-Identifier: MIT\n\n#include <config.h>\necho\n",
+	}
+	for name, content := range want {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Errorf("%s holds %q, want %q", name, b, content)
+		}
+	}
+
+	_, err = Run(context.Background(), Options{
+		Roots:       []string{dir},
+		InsertAfter: []InsertRule{{"**/*.c", "(unclosed"}},
+	})
+	if err == nil {
+		t.Error("Run with an invalid anchor returned no error")
+	}
+}
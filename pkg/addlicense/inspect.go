@@ -0,0 +1,268 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// licenseFileNames lists the names of the files holding the license of a
+// project, in order of preference.
+var licenseFileNames = []string{"LICENSE", "LICENSE.txt", "LICENSE.md", "COPYING", "COPYING.txt"}
+
+// thirdPartyDirs lists the names of the directories that conventionally hold
+// third party code, which keeps its own license headers.
+var thirdPartyDirs = []string{"vendor", "node_modules", "third_party", "external"}
+
+// licensePhrases maps the license types to phrases identifying their text,
+// all of which must be present.
+var licensePhrases = []struct {
+	license string
+	phrases []string
+}{
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"Unlicense", []string{"free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"bsd", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	// the GNU licenses mention each other, the most specific come first
+	{"AGPL-3.0", []string{"gnu affero general public license", "version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	// the phrases of the headers of the licenses whose text they don't hold
+	{"bsd", []string{"governed by a bsd-style license"}},
+	{"CC0-1.0", []string{"cc0 public domain dedication"}},
+}
+
+// Inspection summarizes a tree, to propose the settings of a configuration.
+type Inspection struct {
+	// Extensions holds the number of files of a known type by extension, or
+	// by name for files without one, such as "dockerfile".
+	Extensions map[string]int
+	// LicenseFile is the path of the license file of the tree, if any.
+	LicenseFile string
+	// License is the license type detected in LicenseFile, if any.
+	License string
+	// Holder is the copyright holder named in LicenseFile, if any.
+	Holder string
+	// Ignore lists patterns of the third party directories found in the tree.
+	Ignore []string
+}
+
+// SortedExtensions returns the extensions of the inspection, the most
+// frequent first.
+func (in *Inspection) SortedExtensions() []string {
+	exts := make([]string, 0, len(in.Extensions))
+	for ext := range in.Extensions {
+		exts = append(exts, ext)
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		if in.Extensions[exts[i]] != in.Extensions[exts[j]] {
+			return in.Extensions[exts[i]] > in.Extensions[exts[j]]
+		}
+		return exts[i] < exts[j]
+	})
+	return exts
+}
+
+// Inspect walks the tree at root and reports the types of files found in it,
+// its license file and third party directories. Hidden and third party
+// directories are not walked.
+func Inspect(root string) (*Inspection, error) {
+	in := &Inspection{Extensions: make(map[string]int)}
+	if path, b := readLicenseFile(root); path != "" {
+		in.LicenseFile = path
+		in.License = DetectLicense(b)
+		in.Holder = copyrightHolder(b)
+	}
+
+	err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := fi.Name()
+		if fi.IsDir() {
+			if path == root {
+				return nil
+			}
+			if strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			for _, d := range thirdPartyDirs {
+				if name == d {
+					in.Ignore = append(in.Ignore, "**/"+d+"/**")
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if fileCommentStyle(path) != nil {
+			in.Extensions[fileExtension(strings.ToLower(name))]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	in.Ignore = dedupe(in.Ignore)
+	return in, nil
+}
+
+// readLicenseFile returns the path and contents of the license file of the
+// tree at root, or an empty path if there is none.
+func readLicenseFile(root string) (string, []byte) {
+	for _, name := range licenseFileNames {
+		path := filepath.Join(root, name)
+		if b, err := ioutil.ReadFile(path); err == nil {
+			return path, b
+		}
+	}
+	return "", nil
+}
+
+// DetectLicenseFile returns the path of the license file of the tree at root
+// and the type of its license, see DetectLicense. The path is empty if there
+// is no license file.
+func DetectLicenseFile(root string) (path, license string) {
+	path, b := readLicenseFile(root)
+	if path == "" {
+		return "", ""
+	}
+	return path, DetectLicense(b)
+}
+
+// DetectLicense returns the type of the license whose text is b, such as
+// "Apache-2.0" or "MIT", or an empty string if it isn't recognized.
+func DetectLicense(b []byte) string {
+	lower := bytes.ToLower(bytes.Join(bytes.Fields(b), []byte(" ")))
+	for _, l := range licensePhrases {
+		found := true
+		for _, p := range l.phrases {
+			found = found && bytes.Contains(lower, []byte(p))
+		}
+		if found {
+			return l.license
+		}
+	}
+	return ""
+}
+
+// spdxLicenseID matches an SPDX license identifier line, capturing the
+// license expression without the comment characters closing the line.
+var spdxLicenseID = regexp.MustCompile(`(?im)spdx-license-identifier:[ \t]*(.*?)[ \t]*(?:\*/|-->|\*\)|-\})?[ \t]*$`)
+
+// DetectHeaderLicense returns the type of the license of the license header
+// at the start of b, the contents of a file: the value of its SPDX identifier
+// if any, or the type of the license whose header or text it holds, see
+// DetectLicense. It returns an empty string if the license isn't recognized.
+func DetectHeaderLicense(b []byte) string {
+	head := headWindow(b, 1000)
+	if m := spdxLicenseID.FindSubmatch(head); m != nil {
+		return string(m[1])
+	}
+	// drop the comment characters that start the lines of the header
+	var text []byte
+	for _, line := range bytes.Split(head, []byte("\n")) {
+		text = append(text, bytes.TrimLeftFunc(line, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+		text = append(text, ' ')
+	}
+	return DetectLicense(text)
+}
+
+// hostedOwner matches the URLs and module paths of repositories hosted on
+// well-known forges, capturing the organization or user owning them.
+var hostedOwner = regexp.MustCompile(`(?:github\.com|gitlab\.com|bitbucket\.org)[:/]([^/\s]+)/`)
+
+// DetectHolder derives a copyright holder for the tree at dir from, in order:
+// the owner of its git remote "origin" on a well-known forge, the owner in
+// the module path of its go.mod file, and the git user.name setting. It also
+// returns a description of the source of the holder, for confirmation. The
+// holder is empty if none is found.
+func DetectHolder(dir string) (holder, source string) {
+	if url := gitConfig(dir, "remote.origin.url"); url != "" {
+		if m := hostedOwner.FindStringSubmatch(url); m != nil {
+			return m[1], "git remote origin " + url
+		}
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		if m := hostedOwner.FindSubmatch(modulePath(b)); m != nil {
+			return string(m[1]), "go.mod module " + string(modulePath(b))
+		}
+	}
+	if name := gitConfig(dir, "user.name"); name != "" {
+		return name, "git config user.name"
+	}
+	return "", ""
+}
+
+// gitConfig returns the value of a git setting in the repository at dir, or
+// an empty string if it isn't set.
+func gitConfig(dir, key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// modulePath returns the module path declared by the go.mod file b.
+func modulePath(b []byte) []byte {
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		fields := bytes.Fields(line)
+		if len(fields) >= 2 && string(fields[0]) == "module" {
+			return bytes.Trim(fields[1], `"`)
+		}
+	}
+	return nil
+}
+
+// copyrightHolder returns the holder named by the first copyright statement
+// of b with years, or an empty string if there is none. Statements without
+// years are mostly prose, such as "copyright notice" in the Apache license.
+func copyrightHolder(b []byte) string {
+	for _, sub := range copyrightStatement.FindAllSubmatch(b, -1) {
+		if len(sub[2]) > 0 {
+			return strings.TrimSpace(string(sub[3]))
+		}
+	}
+	return ""
+}
+
+// dedupe returns the sorted distinct elements of s.
+func dedupe(s []string) []string {
+	sort.Strings(s)
+	var out []string
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectLicense(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Apache License\n   Version 2.0, January 2004", "Apache-2.0"},
+		{"MIT License\n\nCopyright (c) 2020 Acme\n\nPermission is hereby granted, free of\ncharge, to any person", "MIT"},
+		{"Mozilla Public License Version 2.0", "MPL-2.0"},
+		{"This is free and unencumbered software released into the public domain.", "Unlicense"},
+		{"Redistribution and use in source and binary forms ... Neither the name of", "bsd"},
+		{"All rights reserved.", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectLicense([]byte(tt.text)); got != tt.want {
+			t.Errorf("DetectLicense(%q) returned %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDetectHeaderLicense(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"// Copyright 2020 Acme\n//\n// Licensed under the Apache License, Version 2.0 (the \"License\");\n", "Apache-2.0"},
+		{"# This Source Code Form is subject to the terms of the Mozilla Public\n# License, v. 2.0.\n", "MPL-2.0"},
+		{"/*\n * Copyright (c) 2020 Acme All rights reserved.\n * Use of this source code is governed by a BSD-style\n * license that can be found in the LICENSE file.\n */\n", "bsd"},
+		{"// Copyright 2020 Acme\n// SPDX-License-Identifier: Apache-2.0 OR MIT\n", "Apache-2.0 OR MIT"},
+		{"/* SPDX-License-Identifier: MIT */\n", "MIT"},
+		{"# This program is free software: you can redistribute it and/or modify\n# it under the terms of the GNU Lesser General Public License as published by\n# the Free Software Foundation, either version 3 of the License\n", "LGPL-3.0"},
+		{"// Copyright 2020 Acme\n\npackage a\n", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectHeaderLicense([]byte(tt.text)); got != tt.want {
+			t.Errorf("DetectHeaderLicense(%q) returned %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestInspect(t *testing.T) {
+	root := tempDir(t)
+	defer os.RemoveAll(root)
+	files := map[string]string{
+		"LICENSE":               "Copyright (c) 2020 Acme Corp\n\nPermission is hereby granted, free of charge, to any person\n",
+		"main.go":               "package main\n",
+		"lib/util.go":           "package lib\n",
+		"scripts/build.sh":      "#!/bin/sh\n",
+		"README.txt":            "readme\n",
+		"vendor/dep/dep.go":     "package dep\n",
+		"web/node_modules/x.js": "x\n",
+		".git/config.sh":        "ignored\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	in, err := Inspect(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{".go": 2, ".sh": 1}; !reflect.DeepEqual(in.Extensions, want) {
+		t.Errorf("Extensions = %v, want %v", in.Extensions, want)
+	}
+	if got := in.SortedExtensions(); !reflect.DeepEqual(got, []string{".go", ".sh"}) {
+		t.Errorf("SortedExtensions returned %v", got)
+	}
+	if in.License != "MIT" || in.Holder != "Acme Corp" {
+		t.Errorf("License, Holder = %q, %q, want %q, %q", in.License, in.Holder, "MIT", "Acme Corp")
+	}
+	if want := []string{"**/node_modules/**", "**/vendor/**"}; !reflect.DeepEqual(in.Ignore, want) {
+		t.Errorf("Ignore = %v, want %v", in.Ignore, want)
+	}
+}
+
+func TestDetectLicenseFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	if path, license := DetectLicenseFile(dir); path != "" || license != "" {
+		t.Errorf("DetectLicenseFile of a tree without license file returned %q, %q", path, license)
+	}
+	want := filepath.Join(dir, "COPYING")
+	if err := ioutil.WriteFile(want, []byte("Mozilla Public License Version 2.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if path, license := DetectLicenseFile(dir); path != want || license != "MPL-2.0" {
+		t.Errorf("DetectLicenseFile returned %q, %q, want %q, %q", path, license, want, "MPL-2.0")
+	}
+}
+
+func TestDetectHolder(t *testing.T) {
+	dir, _ := testRepo(t)
+	defer os.RemoveAll(dir)
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	git("config", "user.name", "Jane Doe")
+	if holder, source := DetectHolder(dir); holder != "Jane Doe" || source != "git config user.name" {
+		t.Errorf("DetectHolder returned %q from %q, want the git user name", holder, source)
+	}
+
+	gomod := "// comment\nmodule \"gitlab.com/acme-corp/tool\"\n\ngo 1.16\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if holder, _ := DetectHolder(dir); holder != "acme-corp" {
+		t.Errorf("DetectHolder returned %q, want the owner of the module", holder)
+	}
+
+	for _, url := range []string{"git@github.com:example/repo.git", "https://github.com/example/repo"} {
+		git("config", "remote.origin.url", url)
+		if holder, _ := DetectHolder(dir); holder != "example" {
+			t.Errorf("DetectHolder with remote %s returned %q, want %q", url, holder, "example")
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	doublestar "github.com/bmatcuk/doublestar/v4"
+)
+
+// LicenseRule assigns a license to the files matching a pattern, so that the
+// subtrees of a repository can use different licenses.
+type LicenseRule struct {
+	Pattern string
+	License string // SPDX identifier or legacy name, such as "MIT" or "mit"
+}
+
+// ErrWrongLicense is the error of files whose license header is not the one
+// of the license assigned to them by a LicenseRule, in check only mode.
+var ErrWrongLicense = errors.New("license header doesn't match the assigned license")
+
+// ErrForbiddenLicense is the error of files whose license header is of one of
+// the forbidden licenses of the run, in check only mode.
+var ErrForbiddenLicense = errors.New("license header of a forbidden license")
+
+// isForbidden reports whether license, a license type or SPDX license
+// expression found in a license header, names one of the forbidden licenses.
+// A forbidden license also matches its variants: "GPL-3.0" forbids
+// "GPL-3.0-only", "GPL-3.0-or-later" and "GPL-3.0+", and "GPL" forbids all
+// versions of the GPL, but not the LGPL.
+func isForbidden(license string, forbidden []string) bool {
+	terms := strings.FieldsFunc(strings.ToLower(license), func(r rune) bool {
+		return r == ' ' || r == '(' || r == ')'
+	})
+	for _, term := range terms {
+		for _, f := range forbidden {
+			f = strings.ToLower(f)
+			if term == f || term == f+"+" || strings.HasPrefix(term, f+"-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LicenseRules returns the rules of licenses, which maps file patterns to
+// licenses, ordered so that the longest, most specific patterns come first.
+func LicenseRules(licenses map[string]string) []LicenseRule {
+	rules := make([]LicenseRule, 0, len(licenses))
+	for p, l := range licenses {
+		rules = append(rules, LicenseRule{Pattern: p, License: l})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].Pattern) != len(rules[j].Pattern) {
+			return len(rules[i].Pattern) > len(rules[j].Pattern)
+		}
+		return rules[i].Pattern < rules[j].Pattern
+	})
+	return rules
+}
+
+// assignedLicense is a license assigned to the files matching pattern.
+type assignedLicense struct {
+	pattern string
+	tmpl    *template.Template
+	data    LicenseData
+}
+
+// parseLicenseRules returns the licenses assigned by rules, rendered in the
+// SPDX mode of the run, with the copyright data of base.
+func parseLicenseRules(rules []LicenseRule, spdx SPDXMode, base LicenseData) ([]assignedLicense, error) {
+	var licenses []assignedLicense
+	for _, rule := range rules {
+		if !doublestar.ValidatePattern(rule.Pattern) {
+			return nil, fmt.Errorf("license pattern %q is not valid", rule.Pattern)
+		}
+		license := rule.License
+		if t, ok := legacyLicenseTypes[license]; ok {
+			license = t
+		}
+		tpl, err := fetchTemplate(license, "", spdx)
+		if err != nil {
+			return nil, fmt.Errorf("license of %q: %w", rule.Pattern, err)
+		}
+		data := base
+		data.SPDXID = license
+		licenses = append(licenses, assignedLicense{
+			pattern: rule.Pattern,
+			tmpl:    template.Must(template.New("").Parse(withNotice(tpl, base.Notice))),
+			data:    data,
+		})
+	}
+	return licenses, nil
+}
+
+// license returns the license template of the file at path and the data to
+// execute it with: those of the first license rule matching path, if any, or
+// those of the run. It returns false if no rule matches.
+func (r *runner) license(path string) (*template.Template, LicenseData, bool) {
+	for _, l := range r.licenses {
+		if fileMatches(path, []string{l.pattern}) {
+			return l.tmpl, l.data, true
+		}
+	}
+	return r.tmpl, r.data, false
+}
+
+// hasAssignedLicense reports whether the license header of b, the contents of
+// the file at path, is the one of the license assigned to it by a rule. Files
+// without a header or without a rule are reported as matching.
+func (r *runner) hasAssignedLicense(path string, b []byte) (bool, error) {
+	tmpl, data, ok := r.license(path)
+	if !ok {
+		return true, nil
+	}
+	style := r.style(path)
+	start, end, ok := licenseBlock(style, path, b, r.markers)
+	if !ok {
+		return true, nil
+	}
+	return isLicenseHeader(style, b[start:end], data.SPDXID, tmpl, data.Notice)
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLicenseRules(t *testing.T) {
+	got := LicenseRules(map[string]string{
+		"sdk/**":        "MIT",
+		"sdk/legacy/**": "BSD-3-Clause",
+		"cmd/**":        "Apache-2.0",
+	})
+	want := []LicenseRule{
+		{"sdk/legacy/**", "BSD-3-Clause"},
+		{"cmd/**", "Apache-2.0"},
+		{"sdk/**", "MIT"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LicenseRules returned %v, want %v", got, want)
+	}
+}
+
+func TestIsForbidden(t *testing.T) {
+	tests := []struct {
+		license string
+		forbid  []string
+		want    bool
+	}{
+		{"GPL-3.0", []string{"GPL-3.0"}, true},
+		{"gpl-3.0-or-later", []string{"GPL-3.0"}, true},
+		{"GPL-3.0+", []string{"GPL-3.0"}, true},
+		{"GPL-2.0-only", []string{"GPL"}, true},
+		{"MIT OR (Apache-2.0 AND GPL-2.0)", []string{"GPL-2.0"}, true},
+		{"LGPL-2.1", []string{"GPL"}, false},
+		{"GPL-3.0", []string{"GPL-2.0"}, false},
+		{"MIT", nil, false},
+	}
+	for _, tt := range tests {
+		if got := isForbidden(tt.license, tt.forbid); got != tt.want {
+			t.Errorf("isForbidden(%q, %q) returned %v, want %v", tt.license, tt.forbid, got, tt.want)
+		}
+	}
+}
+
+func TestForbid(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"a.go": "// Copyright 2020 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n",
+		"b.go": "// Copyright 2020 Someone\n//\n// This program is free software: you can redistribute it and/or modify\n// it under the terms of the GNU General Public License as published by\n// the Free Software Foundation, either version 3 of the License, or\n// (at your option) any later version.\n\npackage b\n",
+		"c.go": "// Copyright 2020 Someone\n// SPDX-License-Identifier: GPL-2.0-or-later\n\npackage c\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := Run(context.Background(), Options{
+		Roots:     []string{dir},
+		License:   "MIT",
+		CheckOnly: true,
+		Forbid:    []string{"GPL"},
+		Logger:    log.New(ioutil.Discard, "", 0),
+	})
+	if err != ErrForbiddenLicense {
+		t.Errorf("Run returned %v, want %v", err, ErrForbiddenLicense)
+	}
+	if report == nil {
+		t.Fatal("Run returned no report")
+	}
+	want := []string{filepath.Join(dir, "b.go"), filepath.Join(dir, "c.go")}
+	if got := report.Paths(StatusForbidden); !reflect.DeepEqual(got, want) {
+		t.Errorf("forbidden files are %q, want %q", got, want)
+	}
+}
+
+func TestSubtreeLicenses(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"cmd/main.go":  "package main\n",
+		"sdk/new.go":   "package sdk\n",
+		"sdk/wrong.go": "// Copyright 2020 Acme\n// SPDX-License-Identifier: Apache-2.0\n\npackage sdk\n",
+		"sdk/right.go": "// Copyright 2020 Acme\n// SPDX-License-Identifier: MIT\n\npackage sdk\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := Options{
+		Roots:     []string{dir},
+		Holder:    "Acme",
+		Year:      "2020",
+		License:   "Apache-2.0",
+		SPDX:      SPDXOnly,
+		Licenses:  []LicenseRule{{"**/sdk/**", "mit"}},
+		CheckOnly: true,
+		Logger:    log.New(ioutil.Discard, "", 0),
+	}
+
+	report, err := Run(context.Background(), opts)
+	if err == nil {
+		t.Fatal("check returned no error")
+	}
+	if got, want := report.Paths(StatusWrongLicense), []string{filepath.Join(dir, "sdk/wrong.go")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("check reported wrong licenses in %q, want %q", got, want)
+	}
+	if got := report.Paths(StatusOK); len(got) != 1 || !strings.HasSuffix(got[0], "right.go") {
+		t.Errorf("check reported ok files %q, want right.go", got)
+	}
+
+	opts.CheckOnly = false
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"cmd/main.go": "SPDX-License-Identifier: Apache-2.0",
+		"sdk/new.go":  "SPDX-License-Identifier: MIT",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%s is %q, want a header with %q", name, b, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package addlicense
+
+import "os"
+
+// linkID reports that hard links can't be detected on this platform.
+func linkID(fi os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
+
+// statID reports that file identities aren't available on this platform.
+func statID(fi os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHardLinks(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.go")
+	if err := ioutil.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(path, filepath.Join(dir, "b.go")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	var logs strings.Builder
+	report, err := Run(context.Background(), Options{
+		Roots:   []string{dir},
+		Holder:  "Acme",
+		Year:    "2020",
+		License: "MIT",
+		Logger:  log.New(&logs, "", 0),
+		Verbose: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 1 {
+		t.Errorf("processed %d files, want 1: %v", len(report.Results), report.Results)
+	}
+	if want := "b.go: hard link to " + path; !strings.Contains(logs.String(), want) {
+		t.Errorf("log doesn't contain %q:\n%s", want, logs.String())
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(b), "Copyright"); n != 1 {
+		t.Errorf("file has %d license headers, want 1:\n%s", n, b)
+	}
+}
+
+func TestFollowSymlinks(t *testing.T) {
+	tmp := tempDir(t)
+	defer os.RemoveAll(tmp)
+	root := filepath.Join(tmp, "root")
+	for _, name := range []string{"root/real/a.go", "ext/b.go"} {
+		path := filepath.Join(tmp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range map[string]string{
+		"root/link":      "real",
+		"root/real/loop": "..",
+		"root/file.go":   "real/a.go",
+		"root/ext":       "../ext",
+	} {
+		if err := os.Symlink(target, filepath.Join(tmp, filepath.FromSlash(link))); err != nil {
+			t.Skipf("symbolic links not supported: %v", err)
+		}
+	}
+
+	for follow, want := range map[bool][]string{
+		false: {"file.go"},
+		true:  {"ext/b.go", "file.go"},
+	} {
+		report, err := Run(context.Background(), Options{
+			Roots:          []string{root},
+			License:        "MIT",
+			CheckOnly:      true,
+			FollowSymlinks: follow,
+			Logger:         log.New(ioutil.Discard, "", 0),
+		})
+		if report == nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, res := range report.Results {
+			rel, _ := filepath.Rel(root, res.Path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("Run(FollowSymlinks: %v) processed %q, want %q", follow, got, want)
+		}
+	}
+}
+
+func TestStreamHardLink(t *testing.T) {
+	defer func(old int64) { streamThreshold = old }(streamThreshold)
+	streamThreshold = 1 << 10
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "print('big')\n" + strings.Repeat("# filler line\n", 1000)
+	path := filepath.Join(src, "big.py")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the other link is outside of the tree, so that the file is processed
+	other := filepath.Join(dir, "other.py")
+	if err := os.Link(path, other); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	if _, err := Run(context.Background(), Options{
+		Roots:   []string{src},
+		License: "MIT",
+		Holder:  "Acme",
+		Year:    "2020",
+		Logger:  log.New(ioutil.Discard, "", 0),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{path, other} {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(b), "# Copyright (c) 2020 Acme\n") || !strings.HasSuffix(string(b), content) {
+			t.Errorf("%s holds %d bytes, want the header followed by the %d bytes of the original:\n%.200s", p, len(b), len(content), b)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import "fmt"
+
+// LogLevel selects the lines logged by a run, from the most verbose level to
+// the quietest. Errors are logged at every level.
+type LogLevel string
+
+const (
+	LogDebug LogLevel = "debug" // also the files that are modified or skipped, as with Verbose
+	LogInfo  LogLevel = ""      // also notes about the run, such as files skipped for their size
+	LogWarn  LogLevel = "warn"  // only warnings and the findings of check only mode
+)
+
+// ParseLogLevel returns the log level named name: "debug", "info" or "warn".
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch name {
+	case string(LogDebug):
+		return LogDebug, nil
+	case "info":
+		return LogInfo, nil
+	case string(LogWarn):
+		return LogWarn, nil
+	}
+	return "", fmt.Errorf("unknown log level %q, expected debug, info or warn", name)
+}
+
+// String returns the name of l.
+func (l LogLevel) String() string {
+	if l == LogInfo {
+		return "info"
+	}
+	return string(l)
+}
+
+// Enabled reports whether lines of the given level are logged at level l.
+func (l LogLevel) Enabled(level LogLevel) bool {
+	return level.rank() >= l.rank()
+}
+
+func (l LogLevel) rank() int {
+	switch l {
+	case LogDebug:
+		return 0
+	case LogWarn:
+		return 2
+	}
+	return 1
+}
+
+// logf logs a line of the given level to l, if enabled at the level of the
+// run.
+func (r *runner) logf(l logger, level LogLevel, format string, v ...interface{}) {
+	enabled := r.opts.LogLevel
+	if r.opts.Verbose {
+		enabled = LogDebug
+	}
+	if enabled.Enabled(level) {
+		l.Printf(format, v...)
+	}
+}
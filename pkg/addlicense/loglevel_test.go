@@ -0,0 +1,90 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	for _, level := range []LogLevel{LogDebug, LogInfo, LogWarn} {
+		got, err := ParseLogLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("ParseLogLevel(%q) returned %q, %v, want %q", level.String(), got, err, level)
+		}
+	}
+	if _, err := ParseLogLevel("trace"); err == nil {
+		t.Error("ParseLogLevel of an unknown level returned no error")
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"a.go":       "package a\n",
+		"big.go":     "package a\n\n" + strings.Repeat("// filler\n", 100),
+		"ignored.go": "package a\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		level   LogLevel
+		verbose bool
+		want    []string
+	}{
+		{LogDebug, false, []string{"big.go", "ignored.go"}},
+		{LogWarn, true, []string{"big.go", "ignored.go"}},
+		{LogInfo, false, []string{"big.go"}},
+		{LogWarn, false, nil},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		_, err := Run(context.Background(), Options{
+			Roots:     []string{dir},
+			License:   "mit",
+			Ignore:    []string{"**/ignored.go"},
+			MaxSize:   100,
+			CheckOnly: true,
+			LogLevel:  tt.level,
+			Verbose:   tt.verbose,
+			Logger:    log.New(&out, "", 0),
+		})
+		if err != ErrMissingLicense {
+			t.Fatalf("Run returned %v, want %v", err, ErrMissingLicense)
+		}
+		// names of the files skipped
+		var got []string
+		for _, line := range strings.Split(out.String(), "\n") {
+			if strings.HasPrefix(line, "skipping: ") {
+				got = append(got, filepath.Base(strings.TrimSuffix(strings.Fields(line)[1], ":")))
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("level %q (verbose %v) logged %q, want %q", tt.level, tt.verbose, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io"
+	"log"
+)
+
+// Option sets a field of the Options of a Licenser. Options not covered by
+// the With functions can be set with a function of its own:
+//
+//	addlicense.New(func(o *addlicense.Options) { o.MaxDepth = 2 })
+type Option func(*Options)
+
+// Licenser adds or checks license headers with a set of Options, for tools
+// that configure it once and run it over several trees.
+type Licenser struct {
+	opts Options
+}
+
+// New returns a Licenser configured by opts, applied in order.
+func New(opts ...Option) *Licenser {
+	l := &Licenser{}
+	for _, opt := range opts {
+		opt(&l.opts)
+	}
+	return l
+}
+
+// Options returns the options of l.
+func (l *Licenser) Options() Options {
+	return l.opts
+}
+
+// Run processes roots, in addition to the roots set by the options of l, as
+// Run does.
+func (l *Licenser) Run(ctx context.Context, roots ...string) (*Report, error) {
+	opts := l.opts
+	opts.Roots = append(append([]string(nil), opts.Roots...), roots...)
+	return Run(ctx, opts)
+}
+
+// WithLicense sets the license type, such as "Apache-2.0" or "mit".
+func WithLicense(license string) Option {
+	return func(o *Options) { o.License = license }
+}
+
+// WithHolder sets the copyright holder.
+func WithHolder(holder string) Option {
+	return func(o *Options) { o.Holder = holder }
+}
+
+// WithYear sets the copyright year(s).
+func WithYear(year string) Option {
+	return func(o *Options) { o.Year = year }
+}
+
+// WithTemplateFile sets the path of a custom license template.
+func WithTemplateFile(path string) Option {
+	return func(o *Options) { o.TemplateFile = path }
+}
+
+// WithSPDX sets whether license headers include an SPDX identifier.
+func WithSPDX(mode SPDXMode) Option {
+	return func(o *Options) { o.SPDX = mode }
+}
+
+// WithIgnore adds patterns of files to skip.
+func WithIgnore(patterns ...string) Option {
+	return func(o *Options) { o.Ignore = append(o.Ignore, patterns...) }
+}
+
+// WithInclude adds patterns of files to process, restricting processing to
+// the files matching one of them. The ignore patterns still apply to those.
+func WithInclude(patterns ...string) Option {
+	return func(o *Options) { o.Include = append(o.Include, patterns...) }
+}
+
+// WithYearPolicy sets how the years of existing license headers are
+// rewritten.
+func WithYearPolicy(policy YearPolicy) Option {
+	return func(o *Options) { o.NormalizeYears = policy }
+}
+
+// WithWorkers sets the number of files processed concurrently.
+func WithWorkers(n int) Option {
+	return func(o *Options) { o.Workers = n }
+}
+
+// WithCheckOnly checks license headers instead of adding missing ones.
+func WithCheckOnly() Option {
+	return func(o *Options) { o.CheckOnly = true }
+}
+
+// WithDryRun writes the diffs of the changes to w instead of making them.
+func WithDryRun(w io.Writer) Option {
+	return func(o *Options) { o.DryRun, o.Diff = true, w }
+}
+
+// WithLogger sets the logger of the run.
+func WithLogger(l *log.Logger) Option {
+	return func(o *Options) { o.Logger = l }
+}
+
+// WithLogLevel sets the level of the lines logged besides errors.
+func WithLogLevel(level LogLevel) Option {
+	return func(o *Options) { o.LogLevel = level }
+}
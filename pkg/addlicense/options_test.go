@@ -0,0 +1,88 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	l := New(
+		WithLicense("mit"),
+		WithHolder("Acme"),
+		WithYear("2026"),
+		WithIgnore("**/vendor/**"),
+		WithIgnore("**/*.pb.go"),
+		WithYearPolicy(YearsRanges),
+		WithWorkers(4),
+		WithCheckOnly(),
+		WithLogger(logger),
+		func(o *Options) { o.MaxDepth = 2 },
+	)
+	want := Options{
+		License:        "mit",
+		Holder:         "Acme",
+		Year:           "2026",
+		Ignore:         []string{"**/vendor/**", "**/*.pb.go"},
+		NormalizeYears: YearsRanges,
+		Workers:        4,
+		CheckOnly:      true,
+		Logger:         logger,
+		MaxDepth:       2,
+	}
+	if got := l.Options(); !reflect.DeepEqual(got, want) {
+		t.Errorf("New returned options %+v, want %+v", got, want)
+	}
+}
+
+func TestLicenserRun(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.go")
+	if err := ioutil.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var diff bytes.Buffer
+	l := New(
+		WithLicense("mit"),
+		WithHolder("Acme"),
+		WithSPDX(SPDXOnly),
+		WithDryRun(&diff),
+		WithLogger(log.New(ioutil.Discard, "", 0)),
+	)
+	report, err := l.Run(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := report.Paths(StatusModified), []string{path}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Run modified %q, want %q", got, want)
+	}
+	if !strings.Contains(diff.String(), "+// SPDX-License-Identifier: MIT") {
+		t.Errorf("Run wrote the diff\n%s\nwhich doesn't add the SPDX identifier", diff.String())
+	}
+	if roots := l.Options().Roots; len(roots) != 0 {
+		t.Errorf("Run changed the roots of the options to %q", roots)
+	}
+}
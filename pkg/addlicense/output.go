@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeOutput completes the output of the file at path, processed with
+// status, in the output directory, at its mirrorPath, as soon as it is
+// processed. The updated contents of modified files are already written
+// there by writeFile, and the others are copied. Files that failed are not
+// written, or removed if partially updated.
+func (r *runner) writeOutput(path string, status Status) error {
+	r.mu.Lock()
+	written := r.output[path]
+	delete(r.output, path)
+	r.mu.Unlock()
+	dst := mirrorPath(r.outputDir, path)
+	if status == StatusError {
+		if written {
+			return os.Remove(dst)
+		}
+		return nil
+	}
+	if written {
+		return nil
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return copyFile(dst, path, fi.Mode().Perm())
+}
+
+// copyFile copies the file at src to dst, created with the permissions fmode
+// if missing.
+func copyFile(dst, src string, fmode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fmode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputDir(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"src/a.go":  "package a\n",
+		"src/b.go":  "// Copyright 2020 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n",
+		"notes.txt": "a\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// the header and the footer are both written to the output, as soon as
+	// each file is processed
+	var late []string
+	_, err = Run(context.Background(), Options{
+		Roots:     []string{"."},
+		Holder:    "Acme",
+		Year:      "2026",
+		License:   "MIT",
+		SPDX:      SPDXOnly,
+		Footers:   map[string]string{"go": "END"},
+		OutputDir: "out",
+		Logger:    log.New(ioutil.Discard, "", 0),
+		OnResult: func(res Result) {
+			if _, err := os.Stat(filepath.Join("out", res.Path)); err != nil {
+				late = append(late, res.Path)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if late != nil {
+		t.Errorf("files not in the output once processed: %v", late)
+	}
+	for name, content := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Errorf("source %s was modified to %q", name, b)
+		}
+	}
+	want := map[string]string{
+		"out/src/a.go":  "// Copyright 2026 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n\n// END\n",
+		"out/src/b.go":  "// Copyright 2020 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n\n// END\n",
+		"out/notes.txt": "a\n",
+	}
+	for name, content := range want {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if string(b) != content {
+			t.Errorf("%s holds %q, want %q", name, b, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out", "out")); !os.IsNotExist(err) {
+		t.Errorf("output directory was walked: %v", err)
+	}
+}
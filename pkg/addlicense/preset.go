@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// preset bundles file patterns for a well-known kind of repository content,
+// so that users don't need to spell them out with individual flags.
+type preset struct {
+	keep   []string // patterns processed even if they match an ignore pattern
+	ignore []string // patterns to ignore
+}
+
+// githubActionsPatterns match GitHub Actions workflow and action definitions.
+// Their header is made of plain YAML comments placed before the document, so
+// keys such as "on:" and any "---" document markers are left intact.
+var githubActionsPatterns = []string{
+	"**/.github/workflows/*.yml",
+	"**/.github/workflows/*.yaml",
+	"**/action.yml",
+	"**/action.yaml",
+}
+
+// terraformPatterns match files managed by Terraform and OpenTofu rather than
+// by hand: downloaded modules and providers, state files, dependency lock files
+// and provider documentation generated by tfplugindocs.
+var terraformPatterns = []string{
+	"**/.terraform/**",
+	"**/*.tfstate",
+	"**/*.tfstate.backup",
+	"**/.terraform.lock.hcl",
+	"**/docs/index.md",
+	"**/docs/{data-sources,ephemeral-resources,functions,guides,resources}/*.md",
+}
+
+var presets = map[string]preset{
+	"github-actions":    {keep: githubActionsPatterns},
+	"no-github-actions": {ignore: githubActionsPatterns},
+	"terraform":         {ignore: terraformPatterns},
+}
+
+// PresetNames returns the sorted names of all known presets.
+func PresetNames() []string {
+	var names []string
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupPreset returns the preset with the given name.
+func lookupPreset(name string) (preset, error) {
+	p, ok := presets[name]
+	if !ok {
+		return preset{}, fmt.Errorf("unknown preset %q, must be one of: %s", name, strings.Join(PresetNames(), ", "))
+	}
+	return p, nil
+}
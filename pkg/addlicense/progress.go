@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import "time"
+
+// Progress describes the progress of a run, see Options.Progress.
+type Progress struct {
+	Found     int           // files found by the walk so far
+	Processed int           // files processed so far
+	WalkDone  bool          // whether the walk is over, Found being the total
+	Elapsed   time.Duration // time since the start of the run
+}
+
+// DefaultProgressInterval is the default interval between the calls of the
+// Progress function of a run.
+const DefaultProgressInterval = time.Second
+
+// progress returns the progress of the run started at start.
+func (r *runner) progress(start time.Time) Progress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Progress{
+		Found:     r.queue.Files,
+		Processed: len(r.results),
+		WalkDone:  r.walkDone,
+		Elapsed:   time.Since(start),
+	}
+}
+
+// startProgress calls the Progress function of the run started at start, if
+// any, every ProgressInterval until the returned function is called, which
+// calls it one last time.
+func (r *runner) startProgress(start time.Time) (stop func()) {
+	if r.opts.Progress == nil {
+		return func() {}
+	}
+	interval := r.opts.ProgressInterval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				r.opts.Progress(r.progress(start))
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		r.opts.Progress(r.progress(start))
+	}
+}
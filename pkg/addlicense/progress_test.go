@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for i := 0; i < 5; i++ {
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", i)), []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var calls []Progress
+	_, err := Run(context.Background(), Options{
+		Roots:            []string{dir},
+		License:          "mit",
+		Logger:           log.New(ioutil.Discard, "", 0),
+		Progress:         func(p Progress) { calls = append(calls, p) },
+		ProgressInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) == 0 {
+		t.Fatal("Progress was never called")
+	}
+	for i := 1; i < len(calls); i++ {
+		if calls[i].Processed < calls[i-1].Processed || calls[i].Found < calls[i-1].Found {
+			t.Errorf("progress went back from %+v to %+v", calls[i-1], calls[i])
+		}
+	}
+	last := calls[len(calls)-1]
+	if last.Found != 5 || last.Processed != 5 || !last.WalkDone {
+		t.Errorf("last progress is %+v, want 5 files found and processed after the walk", last)
+	}
+}
+
+func TestOnResult(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for i := 0; i < 5; i++ {
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", i)), []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var paths []string
+	report, err := Run(context.Background(), Options{
+		Roots:    []string{dir},
+		License:  "mit",
+		Workers:  4,
+		Logger:   log.New(ioutil.Discard, "", 0),
+		OnResult: func(res Result) { paths = append(paths, res.Path) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+	if want := report.Paths(StatusModified); fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("OnResult was called with %q, want %q", paths, want)
+	}
+}
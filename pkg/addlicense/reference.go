@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// referenceTemplate returns a license template holding the text of the
+// license header of the file at path, a file already licensed, so that the
+// headers of other files get the same wording. The years of its copyright
+// statements are replaced with the Year of the run, while the rest of the
+// text, holder included, is kept as is.
+func (r *runner) referenceTemplate(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reference file: %w", err)
+	}
+	style := r.style(path)
+	if style == nil {
+		return "", fmt.Errorf("reference file %s: unknown comment style", path)
+	}
+	start, end, ok := licenseBlock(style, path, b, r.markers)
+	if !ok {
+		return "", fmt.Errorf("reference file %s: no license header found", path)
+	}
+	text := uncommentHeader(style, b[start:end])
+	// the header is text, not template actions
+	text = strings.ReplaceAll(text, "{{", `{{"{{"}}`)
+	return copyrightYears.ReplaceAllStringFunc(text, func(m string) string {
+		sub := copyrightYears.FindStringSubmatch(m)
+		return strings.TrimRight(sub[1], " \t") + "{{ if .Year }} {{.Year}}{{ end }}"
+	}), nil
+}
+
+// uncommentHeader returns the text of block, a license header in style,
+// without its comment markers. Unlike normalizeHeader, it keeps the lines and
+// their indentation, and only drops the blank lines around the text.
+func uncommentHeader(style *commentStyle, block []byte) string {
+	top := []byte(strings.TrimSpace(style.top))
+	mid := []byte(strings.TrimSpace(style.mid))
+	bot := []byte(strings.TrimSpace(style.bot))
+	var lines []string
+	for off := 0; off < len(block); {
+		var line []byte
+		line, off = nextLine(block, off)
+		line = bytes.TrimRight(line, " \t\r\n")
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(top) > 0 && len(lines) == 0 && bytes.HasPrefix(trimmed, top) {
+			line = bytes.TrimPrefix(trimmed[len(top):], []byte(" "))
+			trimmed = bytes.TrimLeft(line, " \t")
+		}
+		if len(bot) > 0 && bytes.HasSuffix(line, bot) {
+			line = bytes.TrimRight(line[:len(line)-len(bot)], " \t")
+			trimmed = bytes.TrimLeft(line, " \t")
+		}
+		if len(mid) > 0 && bytes.HasPrefix(trimmed, mid) {
+			line = bytes.TrimPrefix(trimmed[len(mid):], []byte(" "))
+		}
+		lines = append(lines, string(line))
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ExtractHeader returns the license header of b, the contents of the file at
+// path: the first comment block following any hashbang line or similar
+// preamble, if it mentions a license. If uncomment is set, the comment markers
+// of the header are stripped, as in the templates read from a ReferenceFile.
+// It returns false if the file has no license header or if its comment style
+// is unknown.
+func ExtractHeader(path string, b []byte, uncomment bool) ([]byte, bool) {
+	style := fileCommentStyle(path)
+	start, end, ok := licenseBlock(style, path, b, licenseMarkers)
+	if !ok {
+		return nil, false
+	}
+	if uncomment {
+		return []byte(uncommentHeader(style, b[start:end]) + "\n"), true
+	}
+	return append([]byte(nil), b[start:end]...), true
+}
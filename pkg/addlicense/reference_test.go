@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUncommentHeader(t *testing.T) {
+	tests := []struct {
+		style *commentStyle
+		block string
+		want  string
+	}{
+		{styleSlash, "// Copyright 2019 Acme\n//\n// Licensed under\n//     the terms\n", "Copyright 2019 Acme\n\nLicensed under\n    the terms"},
+		{styleC, "/*\n * Copyright 2019 Acme\n *\n * Licensed\n */\n", "Copyright 2019 Acme\n\nLicensed"},
+		{styleC, "/* Copyright 2019 Acme */\n", "Copyright 2019 Acme"},
+		{styleHash, "# Copyright 2019 Acme\n#\n# Licensed\n", "Copyright 2019 Acme\n\nLicensed"},
+	}
+	for _, tt := range tests {
+		if got := uncommentHeader(tt.style, []byte(tt.block)); got != tt.want {
+			t.Errorf("uncommentHeader(%s, %q) returned %q, want %q", tt.style.name, tt.block, got, tt.want)
+		}
+	}
+}
+
+func TestReferenceFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	ref := filepath.Join(dir, "ref.go")
+	files := map[string]string{
+		ref:                           "// Copyright 2019-2021 Acme Corp\n//\n// Internal use only, see {{docs}}.\n\npackage ref\n",
+		filepath.Join(dir, "a.py"):    "print(1)\n",
+		filepath.Join(dir, "src/b.c"): "int b;\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := Run(context.Background(), Options{
+		Roots:         []string{dir},
+		Holder:        "Ignored",
+		Year:          "2026",
+		License:       "apache",
+		ReferenceFile: ref,
+		Logger:        log.New(ioutil.Discard, "", 0),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		ref:                           files[ref],
+		filepath.Join(dir, "a.py"):    "# Copyright 2026 Acme Corp\n#\n# Internal use only, see {{docs}}.\n\nprint(1)\n",
+		filepath.Join(dir, "src/b.c"): "/*\n * Copyright 2026 Acme Corp\n *\n * Internal use only, see {{docs}}.\n */\n\nint b;\n",
+	}
+	for path, w := range want {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != w {
+			t.Errorf("%s is %q, want %q", path, b, w)
+		}
+	}
+
+	if _, err := Run(context.Background(), Options{
+		Roots:         []string{dir},
+		License:       "apache",
+		ReferenceFile: filepath.Join(dir, "src/b.c.missing"),
+		Logger:        log.New(ioutil.Discard, "", 0),
+	}); err == nil {
+		t.Error("Run with a missing reference file returned no error")
+	}
+}
+
+func TestExtractHeader(t *testing.T) {
+	content := "#!/bin/sh\n# Copyright 2020 Acme\n#\n# Licensed under the MIT license.\n\necho hi\n"
+	tests := []struct {
+		path      string
+		content   string
+		uncomment bool
+		want      string
+		ok        bool
+	}{
+		{"a.sh", content, false, "# Copyright 2020 Acme\n#\n# Licensed under the MIT license.\n", true},
+		{"a.sh", content, true, "Copyright 2020 Acme\n\nLicensed under the MIT license.\n", true},
+		{"a.sh", "# just a comment\necho hi\n", false, "", false},
+		{"a.unknown", content, false, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ExtractHeader(tt.path, []byte(tt.content), tt.uncomment)
+		if string(got) != tt.want || ok != tt.ok {
+			t.Errorf("ExtractHeader(%s, %q, %v) returned %q, %v, want %q, %v", tt.path, tt.content, tt.uncomment, got, ok, tt.want, tt.ok)
+		}
+	}
+}
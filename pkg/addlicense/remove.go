@@ -0,0 +1,52 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"os"
+)
+
+// removeLicense removes the license header of the file at path, if any.
+//
+// It returns true if the file was updated.
+func (r *runner) removeLicense(path string, fmode os.FileMode) (bool, error) {
+	b, err := r.readFile(path)
+	if err != nil {
+		return false, err
+	}
+	nb, ok := removeLicenseIn(r.style(path), path, b, r.markers)
+	if !ok {
+		return false, nil
+	}
+	return true, r.writeFile(path, nb, fmode)
+}
+
+// removeLicenseIn returns b without its license header, as located by
+// licenseBlock, and the blank line separating it from the rest of the file.
+// b holds the contents of the file at path, commented in style. It returns
+// false if b has no license header.
+func removeLicenseIn(style *commentStyle, path string, b []byte, markers [][]byte) ([]byte, bool) {
+	start, end, ok := licenseBlock(style, path, b, markers)
+	if !ok {
+		return b, false
+	}
+	if line, next := nextLine(b, end); end < len(b) && len(bytes.TrimSpace(line)) == 0 {
+		end = next
+	}
+	nb := make([]byte, 0, len(b)-(end-start))
+	nb = append(nb, b[:start]...)
+	return append(nb, b[end:]...), true
+}
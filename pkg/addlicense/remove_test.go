@@ -0,0 +1,96 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoveLicenseIn(t *testing.T) {
+	tests := []struct {
+		path    string
+		content string
+		want    string
+	}{
+		{"f.go", "// Copyright 2020 Acme\n// Licensed under MIT.\n\npackage main\n", "package main\n"},
+		{"f.go", "// Copyright 2020 Acme\npackage main\n", "package main\n"},
+		{"f.go", "// Copyright 2020 Acme\n", ""},
+		{"f.c", "/*\n * Copyright 2020 Acme\n */\n\nint x;\n\n\n", "int x;\n\n\n"},
+		{"f.sh", "#!/bin/sh\n# Copyright 2020 Acme\n\necho\n", "#!/bin/sh\necho\n"},
+		{"f.sh", "#!/bin/sh\n\n# Copyright 2020 Acme\n\necho\n", "#!/bin/sh\n\necho\n"},
+		{"f.html", "<!doctype html>\n<!--\n Copyright 2020 Acme\n-->\n\n<p>\n", "<!doctype html>\n<p>\n"},
+		{"f.py", "# ---\n# jupyter:\n# ---\n\n# Copyright 2020 Acme\n\n# %%\n", "# ---\n# jupyter:\n# ---\n\n# %%\n"},
+
+		// doc comments directly following the license text are kept
+		{"a.go", "// Copyright 2020 Acme\n// Package a frobs.\npackage a\n", "// Package a frobs.\npackage a\n"},
+		{"a.go", "// Copyright 2020 Acme\n//\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n//\n// Package a frobs.\npackage a\n", "// Package a frobs.\npackage a\n"},
+		{"a.go", "// Copyright 2020 Acme\n// Package a frobs.\n\npackage a\n", "package a\n"},
+
+		// files without license header are left alone
+		{"f.go", "// Package main does things.\npackage main\n", "// Package main does things.\npackage main\n"},
+		{"f.go", "package main\n\n// Copyright 2020 Acme\n", "package main\n\n// Copyright 2020 Acme\n"},
+		{"f.txt", "Copyright 2020 Acme\n", "Copyright 2020 Acme\n"},
+	}
+	for _, tt := range tests {
+		got, ok := removeLicenseIn(fileCommentStyle(tt.path), tt.path, []byte(tt.content), licenseMarkers)
+		if string(got) != tt.want {
+			t.Errorf("removeLicenseIn(%q, %q) returned %q, want %q", tt.path, tt.content, got, tt.want)
+		}
+		if ok != (tt.content != tt.want) {
+			t.Errorf("removeLicenseIn(%q, %q) returned %v", tt.path, tt.content, ok)
+		}
+	}
+}
+
+func TestRemoveInsertedNotebookLicense(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sales.py")
+	const original = "# Analysis of the sales data\n# %%\nimport os\n"
+	if err := ioutil.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{
+		Roots:   []string{dir},
+		License: "MIT",
+		Holder:  "Acme",
+		Year:    "2020",
+		Logger:  log.New(ioutil.Discard, "", 0),
+	}
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "# Copyright (c) 2020 Acme\n") || !strings.HasSuffix(string(b), "\n\n"+original) {
+		t.Errorf("license header not inserted above the leading comment:\n%s", b)
+	}
+
+	opts.Remove = true
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(path); string(b) != original {
+		t.Errorf("removing the license header left %q, want %q", b, original)
+	}
+}
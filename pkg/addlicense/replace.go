@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// replaceLicense replaces the license header of the file at path with the
+// license header of the run, if it is a header of the license being replaced.
+// The holder and years of its copyright statement, if any, are preserved.
+//
+// It returns true if the file was updated.
+func (r *runner) replaceLicense(path string, fmode os.FileMode) (bool, error) {
+	b, err := r.readFile(path)
+	if err != nil {
+		return false, err
+	}
+	style := r.style(path)
+	start, end, ok := licenseBlock(style, path, b, r.markers)
+	if !ok {
+		return false, nil
+	}
+	match, err := isLicenseHeader(style, b[start:end], r.opts.Replace, r.replaceTmpl, r.opts.Notice)
+	if err != nil || !match {
+		return false, err
+	}
+
+	tmpl, data, _ := r.license(path)
+	if sub := copyrightStatement.FindSubmatch(b[start:end]); sub != nil {
+		data.Year = string(sub[2])
+		holder := sub[3]
+		// short headers may hold the SPDX identifier on the same line
+		if loc := spdxIdentifier.FindIndex(holder); loc != nil {
+			holder = bytes.TrimSuffix(bytes.TrimSpace(holder[:loc[0]]), []byte("."))
+		}
+		data.Holder = string(bytes.TrimSpace(holder))
+	}
+	if r.opts.KeepShort && isShortHeader(style, b[start:end], r.opts.Notice) {
+		tmpl = r.shortTmpl
+	}
+	lic, err := executeTemplate(tmpl, data, style.top, style.mid, style.bot)
+	if err != nil {
+		return false, err
+	}
+	// the new header ends with its own blank line
+	if line, next := nextLine(b, end); end < len(b) && len(bytes.TrimSpace(line)) == 0 {
+		end = next
+	}
+	nb := make([]byte, 0, len(b)-(end-start)+len(lic))
+	nb = append(nb, b[:start]...)
+	nb = append(nb, lic...)
+	return true, r.writeFile(path, append(nb, b[end:]...), fmode)
+}
+
+// isLicenseHeader reports whether block, a license header in style, is a
+// header of license, whose template is tmpl: either it has the SPDX
+// identifier of license, or its text is the one of tmpl, regardless of the
+// holder and years of their copyright statements, of whitespace and of the
+// additional notice, if any.
+func isLicenseHeader(style *commentStyle, block []byte, license string, tmpl *template.Template, notice string) (bool, error) {
+	if bytes.Contains(bytes.ToLower(block), bytes.ToLower([]byte("SPDX-License-Identifier: "+license))) {
+		return true, nil
+	}
+	want, err := executeTemplate(tmpl, LicenseData{Year: "2000", Holder: "HOLDER", SPDXID: license}, style.top, style.mid, style.bot)
+	if err != nil {
+		return false, err
+	}
+	return headerBody(style, block, notice) == headerBody(style, want, notice), nil
+}
+
+// headerBody returns the normalized text of block, a license header in style,
+// without its copyright statements and without the additional notice.
+func headerBody(style *commentStyle, block []byte, notice string) string {
+	text := normalizeHeader(style, copyrightStatement.ReplaceAll(block, nil))
+	if notice == "" {
+		return text
+	}
+	n := normalizeHeader(&commentStyle{}, []byte(notice))
+	return strings.Join(strings.Fields(strings.Replace(text, n, "", 1)), " ")
+}
+
+// spdxIdentifier matches an SPDX license identifier ending a line.
+var spdxIdentifier = regexp.MustCompile(`(?i)spdx-license-identifier:\s*\S+$`)
+
+// isShortHeader reports whether block, a license header in style, is a short
+// header: copyright statements followed by an SPDX identifier, possibly on the
+// same line, and nothing else but the lines of the additional notice.
+func isShortHeader(style *commentStyle, block []byte, notice string) bool {
+	top := []byte(strings.TrimSpace(style.top))
+	mid := []byte(strings.TrimSpace(style.mid))
+	bot := []byte(strings.TrimSpace(style.bot))
+	noticeLines := make(map[string]bool)
+	for _, l := range strings.Split(notice, "\n") {
+		noticeLines[strings.TrimSpace(l)] = true
+	}
+	spdx := false
+	for off := 0; off < len(block); {
+		var line []byte
+		line, off = nextLine(block, off)
+		line = bytes.TrimSpace(line)
+		if len(top) > 0 {
+			line = bytes.TrimPrefix(line, top)
+		}
+		if len(bot) > 0 {
+			line = bytes.TrimSuffix(line, bot)
+		}
+		line = bytes.TrimSpace(line)
+		if len(mid) > 0 {
+			line = bytes.TrimSpace(bytes.TrimPrefix(line, mid))
+		}
+		if len(line) == 0 || noticeLines[string(line)] {
+			continue
+		}
+		if spdx {
+			return false
+		}
+		if loc := spdxIdentifier.FindIndex(line); loc != nil {
+			spdx = true
+			line = bytes.TrimSpace(line[:loc[0]])
+			if len(line) == 0 {
+				continue
+			}
+		}
+		if !copyrightStatement.Match(line) {
+			return false
+		}
+	}
+	return spdx
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+func TestReplaceLicense(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	r := &runner{
+		opts:        Options{Replace: "MIT"},
+		log:         log.New(ioutil.Discard, "", 0),
+		tmpl:        template.Must(template.New("").Parse(tmplBSD)),
+		replaceTmpl: template.Must(template.New("").Parse(tmplMIT)),
+		data:        LicenseData{Holder: "Default", Year: "2026"},
+		markers:     licenseMarkers,
+	}
+	mit, err := executeTemplate(r.replaceTmpl, LicenseData{Holder: "Acme Corp", Year: "2018-2019"}, "", "// ", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{
+			"#!/usr/bin/env gorun\n" + string(mit) + "package main\n",
+			"#!/usr/bin/env gorun\n// Copyright (c) 2018-2019 Acme Corp All rights reserved.\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n\npackage main\n",
+		},
+		{
+			"// SPDX-License-Identifier: MIT\npackage main\n",
+			"// Copyright (c) 2026 Default All rights reserved.\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n\npackage main\n",
+		},
+		// doc comments directly following the license text are kept
+		{
+			"// SPDX-License-Identifier: MIT\n// Package main frobs.\npackage main\n",
+			"// Copyright (c) 2026 Default All rights reserved.\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n\n// Package main frobs.\npackage main\n",
+		},
+		// headers of other licenses are left alone
+		{
+			"// Copyright 2019 Acme Corp\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
+			"// Copyright 2019 Acme Corp\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
+		},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, "file.go")
+		if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modified, err := r.replaceLicense(path, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != tt.want || modified != (tt.content != tt.want) {
+			t.Errorf("replaceLicense of %q returned %v, wrote %q, want %q", tt.content, modified, got, tt.want)
+		}
+	}
+}
+
+func TestReplaceShortHeader(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	r := &runner{
+		opts:        Options{Replace: "MIT", KeepShort: true},
+		log:         log.New(ioutil.Discard, "", 0),
+		tmpl:        template.Must(template.New("").Parse(tmplApache)),
+		replaceTmpl: template.Must(template.New("").Parse(tmplMIT)),
+		shortTmpl:   template.Must(template.New("").Parse(tmplShort)),
+		data:        LicenseData{Holder: "Default", Year: "2026", SPDXID: "Apache-2.0"},
+		markers:     licenseMarkers,
+	}
+	want := "// Copyright 2019 Acme Corp\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n"
+	for _, content := range []string{
+		"// Copyright 2019 Acme Corp\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+		"// Copyright 2019 Acme Corp. SPDX-License-Identifier: MIT\n\npackage main\n",
+	} {
+		path := filepath.Join(dir, "file.go")
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.replaceLicense(path, 0644); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != want {
+			t.Errorf("replaceLicense of %q wrote %q, want %q", content, got, want)
+		}
+	}
+}
+
+func TestIsShortHeader(t *testing.T) {
+	tests := []struct {
+		path   string
+		block  string
+		notice string
+		want   bool
+	}{
+		{"f.go", "// Copyright 2019 Acme Corp\n// SPDX-License-Identifier: MIT\n", "", true},
+		{"f.go", "// SPDX-License-Identifier: MIT\n", "", true},
+		{"f.py", "# Copyright 2019 Acme Corp\n# Copyright 2020 Other\n#\n# SPDX-License-Identifier: MIT\n", "", true},
+		{"f.c", "/*\n * Copyright 2019 Acme Corp\n * SPDX-License-Identifier: MIT\n */\n", "", true},
+		{"f.c", "/* Copyright 2019 Acme Corp. SPDX-License-Identifier: MIT */\n", "", true},
+
+		{"f.go", "// Copyright 2019 Acme Corp\n", "", false},
+		{"f.go", "// Copyright 2019 Acme Corp\n// SPDX-License-Identifier: MIT\n// Permission is hereby granted\n", "", false},
+		{"f.go", "// SPDX-License-Identifier: MIT\n// Copyright 2019 Acme Corp\n", "", false},
+		{"f.go", "// Permission is hereby granted. SPDX-License-Identifier: MIT\n", "", false},
+
+		{"f.go", "// Copyright 2019 Acme Corp\n// SPDX-License-Identifier: MIT\n//\n// Export controlled.\n// See EXPORT.md.\n", "Export controlled.\nSee EXPORT.md.", true},
+		{"f.go", "// Copyright 2019 Acme Corp\n// SPDX-License-Identifier: MIT\n//\n// Export controlled.\n", "", false},
+	}
+	for _, tt := range tests {
+		if got := isShortHeader(fileCommentStyle(tt.path), []byte(tt.block), tt.notice); got != tt.want {
+			t.Errorf("isShortHeader(%q, %q, %q) returned %v, want %v", tt.path, tt.block, tt.notice, got, tt.want)
+		}
+	}
+}
+
+func TestNotice(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	const notice = "Acme is a trademark of Acme Corp."
+	files := map[string]string{
+		"new.go": "package a\n",
+		"mit.go": "// Copyright (c) 2019 Acme\n//\n// Permission is hereby granted, free of charge, to any person obtaining a copy of\n// this software and associated documentation files (the \"Software\"), to deal in\n// the Software without restriction, including without limitation the rights to\n// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of\n// the Software, and to permit persons to whom the Software is furnished to do so,\n// subject to the following conditions:\n//\n// The above copyright notice and this permission notice shall be included in all\n// copies or substantial portions of the Software.\n//\n// THE SOFTWARE IS PROVIDED \"AS IS\", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR\n// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS\n// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR\n// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER\n// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN\n// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.\n//\n// " + notice + "\n\npackage a\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := Options{
+		Roots:   []string{dir},
+		Holder:  "Acme",
+		Year:    "2026",
+		License: "bsd",
+		Replace: "mit",
+		Notice:  notice,
+		Logger:  log.New(ioutil.Discard, "", 0),
+	}
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"new.go": "// Copyright (c) 2026 Acme All rights reserved.\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n//\n// " + notice + "\n\npackage a\n",
+		"mit.go": "// Copyright (c) 2019 Acme All rights reserved.\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n//\n// " + notice + "\n\npackage a\n",
+	}
+	for name, content := range want {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Errorf("%s holds %q, want %q", name, b, content)
+		}
+	}
+
+	// headers with the notice are those of their assigned license
+	opts.Replace, opts.CheckOnly = "", true
+	opts.Licenses = []LicenseRule{{"**", "bsd"}}
+	report, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.Count(StatusOK); got != 2 {
+		t.Errorf("check reported %d ok files, want 2", got)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import "time"
+
+// Status is the outcome of processing a file.
+type Status string
+
+const (
+	StatusOK            Status = "ok"             // the file has a license header
+	StatusModified      Status = "modified"       // the file was updated
+	StatusMissing       Status = "missing"        // the file is missing a license header, in check only mode
+	StatusDuplicate     Status = "duplicate"      // the file has a license header stacked twice, in check only mode
+	StatusMissingFooter Status = "missing-footer" // the file is missing a required license footer, in check only mode
+	StatusWrongLicense  Status = "wrong-license"  // the license header isn't the one assigned to the file, in check only mode
+	StatusForbidden     Status = "forbidden"      // the license header is of a forbidden license, in check only mode
+	StatusSkipped       Status = "skipped"        // the file type is unknown
+	StatusBinary        Status = "binary"         // the file holds binary data, see isBinary
+	StatusMinified      Status = "minified"       // the file is minified JavaScript or CSS, see isMinified
+	StatusWarning       Status = "warning"        // the file could not be processed, but the error was downgraded
+	StatusError         Status = "error"          // the file could not be processed
+)
+
+// Result is the outcome of processing a file.
+type Result struct {
+	Path     string
+	Status   Status
+	Err      error         // error processing the file, if any
+	Start    time.Time     // start of processing the file
+	Duration time.Duration // time spent processing the file
+
+	// WriteStart and WriteEnd are the start of the first write of the file
+	// and the end of its last one, or zero if it wasn't written.
+	WriteStart, WriteEnd time.Time
+
+	// License and Holder are the license type and the copyright holder found
+	// in the license header of the file, in check only mode. They are empty
+	// if the file has no license header or they aren't recognized, see
+	// DetectHeaderLicense.
+	License string
+	Holder  string
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	// Results holds the outcome of each processed file, sorted by path.
+	// Ignored files are not included.
+	Results []Result
+
+	Start   time.Time // start of the run
+	WalkEnd time.Time // end of walking the file trees
+	End     time.Time // end of the run
+
+	// WriteDuration is the total time spent writing files.
+	WriteDuration time.Duration
+	// Queue describes the queue of files waiting for a worker.
+	Queue QueueStats
+}
+
+// QueueStats describes the queue of the files found by the walk of a run
+// waiting to be processed.
+type QueueStats struct {
+	Workers   int           // number of files processed concurrently
+	Capacity  int           // number of files that may wait in the queue
+	Files     int           // number of files queued
+	MaxDepth  int           // most files waiting at once
+	MeanDepth float64       // mean number of files waiting when one is queued
+	Blocked   time.Duration // time the walk waited for room in the queue
+}
+
+// Paths returns the sorted paths of the files with the given status.
+func (r *Report) Paths(status Status) []string {
+	var paths []string
+	for _, res := range r.Results {
+		if res.Status == status {
+			paths = append(paths, res.Path)
+		}
+	}
+	return paths
+}
+
+// UnknownExtensions returns the number of files skipped because their type is
+// unknown, by extension, or by name for files without extension.
+func (r *Report) UnknownExtensions() map[string]int {
+	exts := make(map[string]int)
+	for _, res := range r.Results {
+		if res.Status == StatusSkipped {
+			exts[extKey(res.Path)]++
+		}
+	}
+	return exts
+}
+
+// ErrorClasses returns the number of files that failed with an error, by
+// class of error, see ErrClassPermission and the other classes.
+func (r *Report) ErrorClasses() map[string]int {
+	classes := make(map[string]int)
+	for _, res := range r.Results {
+		if res.Status == StatusError {
+			classes[errorClass(res.Err)]++
+		}
+	}
+	return classes
+}
+
+// Stats aggregates the results of a run, for compliance dashboards.
+type Stats struct {
+	// Extensions maps extensions, or names of files without one, to the
+	// number of files with each status.
+	Extensions map[string]map[Status]int `json:"extensions"`
+	// Licenses maps the license types found in license headers to their
+	// number of files.
+	Licenses map[string]int `json:"licenses"`
+	// Holders maps the copyright holders found in license headers to their
+	// number of files.
+	Holders map[string]int `json:"holders"`
+}
+
+// Stats returns the aggregates of the results of the report.
+func (r *Report) Stats() Stats {
+	st := Stats{
+		Extensions: make(map[string]map[Status]int),
+		Licenses:   make(map[string]int),
+		Holders:    make(map[string]int),
+	}
+	for _, res := range r.Results {
+		ext := extKey(res.Path)
+		if st.Extensions[ext] == nil {
+			st.Extensions[ext] = make(map[Status]int)
+		}
+		st.Extensions[ext][res.Status]++
+		if res.License != "" {
+			st.Licenses[res.License]++
+		}
+		if res.Holder != "" {
+			st.Holders[res.Holder]++
+		}
+	}
+	return st
+}
+
+// Count returns the number of files with the given status.
+func (r *Report) Count(status Status) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	doublestar "github.com/bmatcuk/doublestar/v4"
+)
+
+// Classes of errors encountered while processing files.
+const (
+	ErrClassPermission = "permission" // permission denied, such as read-only mounts
+	ErrClassNotExist   = "not-exist"  // file removed while running
+	ErrClassTimeout    = "timeout"    // file not processed within FileTimeout
+	ErrClassIO         = "io"         // any other error
+)
+
+// errorClass returns the class of err.
+func errorClass(err error) string {
+	switch {
+	case err == ErrFileTimeout:
+		return ErrClassTimeout
+	case os.IsPermission(err):
+		return ErrClassPermission
+	case os.IsNotExist(err):
+		return ErrClassNotExist
+	default:
+		return ErrClassIO
+	}
+}
+
+// WarnRule downgrades the errors of a class to warnings for the files
+// matching Pattern, or for all files if Pattern is empty.
+type WarnRule struct {
+	Class   string
+	Pattern string
+}
+
+// ParseWarnRule parses a rule of the form "class[=pattern]".
+func ParseWarnRule(value string) (WarnRule, error) {
+	class, pattern := value, ""
+	if i := strings.Index(value, "="); i >= 0 {
+		class, pattern = value[:i], value[i+1:]
+	}
+	switch class {
+	case ErrClassPermission, ErrClassNotExist, ErrClassTimeout, ErrClassIO:
+	default:
+		return WarnRule{}, fmt.Errorf("expected one of the classes %s, %s, %s or %s", ErrClassPermission, ErrClassNotExist, ErrClassTimeout, ErrClassIO)
+	}
+	if pattern != "" && !doublestar.ValidatePattern(pattern) {
+		return WarnRule{}, fmt.Errorf("pattern %q is not valid", pattern)
+	}
+	return WarnRule{class, pattern}, nil
+}
+
+// downgrade reports whether err, which occurred processing the file at path,
+// is downgraded to a warning by one of the rules.
+func downgrade(rules []WarnRule, path string, err error) bool {
+	class := errorClass(err)
+	for _, rule := range rules {
+		if rule.Class == class && (rule.Pattern == "" || fileMatches(path, []string{rule.Pattern})) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportError logs err, which occurred processing the file at path, to l,
+// annotated with its severity and class. It returns nil if err is downgraded to a
+// warning by the rules of the run, and err otherwise.
+func (r *runner) reportError(l logger, path string, err error) error {
+	class := errorClass(err)
+	if downgrade(r.opts.Warn, path, err) {
+		l.Printf("warning: %s: %v [%s]", path, err, class)
+		return nil
+	}
+	l.Printf("error: %s: %v [%s]", path, err, class)
+	return err
+}
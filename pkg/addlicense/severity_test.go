@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWarnRules(t *testing.T) {
+	var rules []WarnRule
+	for _, v := range []string{"permission=vendor/**", "not-exist", "timeout=mnt/**"} {
+		rule, err := ParseWarnRule(v)
+		if err != nil {
+			t.Fatalf("ParseWarnRule(%q) returned %v", v, err)
+		}
+		rules = append(rules, rule)
+	}
+	for _, v := range []string{"fatal", "io=[", "=vendor/**"} {
+		if _, err := ParseWarnRule(v); err == nil {
+			t.Errorf("ParseWarnRule(%q) returned no error", v)
+		}
+	}
+
+	permErr := &os.PathError{Op: "open", Path: "f", Err: os.ErrPermission}
+	notExistErr := &os.PathError{Op: "open", Path: "f", Err: os.ErrNotExist}
+	tests := []struct {
+		path string
+		err  error
+		want bool
+	}{
+		{"vendor/lib/file.go", permErr, true},
+		{"src/file.go", permErr, false},
+		{"src/file.go", notExistErr, true},
+		{"vendor/lib/file.go", errors.New("disk full"), false},
+		{"mnt/file.go", ErrFileTimeout, true},
+		{"src/file.go", ErrFileTimeout, false},
+	}
+	for _, tt := range tests {
+		if got := downgrade(rules, tt.path, tt.err); got != tt.want {
+			t.Errorf("downgrade(%q, %v) returned %v, want %v", tt.path, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&os.PathError{Op: "open", Path: "f", Err: os.ErrPermission}, ErrClassPermission},
+		{&os.PathError{Op: "open", Path: "f", Err: os.ErrNotExist}, ErrClassNotExist},
+		{ErrFileTimeout, ErrClassTimeout},
+		{errors.New("disk full"), ErrClassIO},
+	}
+	for _, tt := range tests {
+		if got := errorClass(tt.err); got != tt.want {
+			t.Errorf("errorClass(%v) returned %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestErrorClasses(t *testing.T) {
+	report := &Report{Results: []Result{
+		{Path: "a", Status: StatusError, Err: &os.PathError{Op: "open", Path: "a", Err: os.ErrPermission}},
+		{Path: "b", Status: StatusError, Err: errors.New("disk full")},
+		{Path: "c", Status: StatusError, Err: &os.PathError{Op: "open", Path: "c", Err: os.ErrPermission}},
+		{Path: "d", Status: StatusWarning},
+		{Path: "e", Status: StatusMissing, Err: ErrMissingLicense},
+	}}
+	want := map[string]int{ErrClassPermission: 2, ErrClassIO: 1}
+	if got := report.ErrorClasses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ErrorClasses returned %v, want %v", got, want)
+	}
+}
+
+func TestTolerateErrors(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	// files that don't parse fail Go verification
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("package a\nfunc {\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "c.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		tolerate int
+		fail     bool
+	}{
+		{0, true},
+		{1, true},
+		{2, false},
+	} {
+		report, err := Run(context.Background(), Options{
+			Roots:          []string{dir},
+			Holder:         "Acme",
+			License:        "MIT",
+			SPDX:           SPDXOnly,
+			VerifyGo:       true,
+			DryRun:         true,
+			Diff:           ioutil.Discard,
+			TolerateErrors: tt.tolerate,
+			Logger:         log.New(ioutil.Discard, "", 0),
+		})
+		if (err != nil) != tt.fail {
+			t.Errorf("Run tolerating %d errors returned %v, want failure %v", tt.tolerate, err, tt.fail)
+		}
+		if report == nil {
+			t.Fatal("Run returned no report")
+		}
+		if got := report.Count(StatusError); got != 2 {
+			t.Errorf("Run tolerating %d errors reported %d errors, want 2", tt.tolerate, got)
+		}
+		if got := report.Count(StatusModified); got != 1 {
+			t.Errorf("Run tolerating %d errors reported %d modified files, want 1", tt.tolerate, got)
+		}
+	}
+}
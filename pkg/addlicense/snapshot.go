@@ -0,0 +1,255 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// snapshotManifest is the name of the manifest of a snapshot directory.
+const snapshotManifest = "manifest.json"
+
+// snapshotJournal is the name of the journal of a snapshot directory, to
+// which an entry is appended as each file is saved, so that a run that
+// doesn't get to write the manifest can still be rolled back.
+const snapshotJournal = "journal.json"
+
+// SnapshotEntry records a file modified by a run.
+type SnapshotEntry struct {
+	// Path is the absolute path of the file.
+	Path string      `json:"path"`
+	Mode os.FileMode `json:"mode"`
+	// Before and After are the SHA-256 hashes of the file contents before
+	// and after the run.
+	Before string `json:"before"`
+	After  string `json:"after"`
+	// Saved is the name of the copy of the original contents in the
+	// snapshot directory.
+	Saved string `json:"saved"`
+}
+
+// snapshot saves the original contents of the files modified by a run to a
+// directory, so that the run can be rolled back.
+type snapshot struct {
+	dir string
+
+	mu      sync.Mutex
+	journal *os.File // created with the first entry
+	entries []SnapshotEntry
+	index   map[string]int // index of the entry of each absolute path
+}
+
+// newSnapshot creates the snapshot directory dir, which must not hold a
+// snapshot already.
+func newSnapshot(dir string) (*snapshot, error) {
+	for _, name := range []string{snapshotManifest, snapshotJournal} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return nil, fmt.Errorf("snapshot %s already exists", dir)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{dir: abs, index: make(map[string]int)}, nil
+}
+
+// save saves old, the contents of the file at path about to be replaced by
+// new, unless it was saved already: a file updated several times during a run
+// keeps its original contents, and only the hash of its final contents is
+// updated.
+func (s *snapshot) save(path string, mode os.FileMode, old, new []byte) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.index[abs]; ok {
+		s.entries[i].After = hashContents(new)
+		return s.appendJournal(s.entries[i])
+	}
+	e := SnapshotEntry{
+		Path:   abs,
+		Mode:   mode,
+		Before: hashContents(old),
+		After:  hashContents(new),
+		Saved:  fmt.Sprintf("%06d", len(s.entries)+1),
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.dir, e.Saved), old, 0600); err != nil {
+		return err
+	}
+	if err := s.appendJournal(e); err != nil {
+		return err
+	}
+	s.index[abs] = len(s.entries)
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// appendJournal appends e to the journal, before its file is replaced.
+func (s *snapshot) appendJournal(e SnapshotEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if s.journal == nil {
+		if s.journal, err = os.OpenFile(filepath.Join(s.dir, snapshotJournal), os.O_WRONLY|os.O_CREATE|os.O_EXCL|os.O_APPEND, 0644); err != nil {
+			return err
+		}
+	}
+	_, err = s.journal.Write(append(b, '\n'))
+	return err
+}
+
+// writeManifest writes the manifest of the files saved so far, which then
+// replaces the journal.
+func (s *snapshot) writeManifest() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = replaceFile(filepath.Join(s.dir, snapshotManifest), 0644, func(w io.Writer) error {
+		_, err := w.Write(append(b, '\n'))
+		return err
+	})
+	if err != nil || s.journal == nil {
+		return err
+	}
+	journal := s.journal
+	s.journal = nil
+	if err := journal.Close(); err != nil {
+		return err
+	}
+	return os.Remove(journal.Name())
+}
+
+// readJournal returns the entries of the journal of the snapshot in dir. A
+// file saved several times keeps its first entry, with the last hash of its
+// contents after the run. An entry cut short by the end of the run is
+// ignored.
+func readJournal(dir string) ([]SnapshotEntry, error) {
+	f, err := os.Open(filepath.Join(dir, snapshotJournal))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []SnapshotEntry
+	index := make(map[string]int)
+	dec := json.NewDecoder(f)
+	for {
+		var e SnapshotEntry
+		err := dec.Decode(&e)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name(), err)
+		}
+		if i, ok := index[e.Path]; ok {
+			entries[i].After = e.After
+			continue
+		}
+		index[e.Path] = len(entries)
+		entries = append(entries, e)
+	}
+}
+
+func hashContents(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// ErrSnapshotConflict is the error of files modified since the snapshot of a
+// run was taken, which a rollback leaves alone unless forced.
+var ErrSnapshotConflict = errors.New("modified since the snapshot")
+
+// RollbackResult is the outcome of restoring a file of a snapshot.
+type RollbackResult struct {
+	Path string
+	// Err is nil if the file was restored.
+	Err error
+}
+
+// Rollback restores the files of the snapshot in dir to their contents
+// before the run that took it. Files modified since the run fail with
+// ErrSnapshotConflict and are left alone, unless force is set. Files already
+// restored are skipped. Snapshots of runs that ended before writing their
+// manifest are restored from their journal.
+func Rollback(dir string, force bool) ([]RollbackResult, error) {
+	var entries []SnapshotEntry
+	b, err := ioutil.ReadFile(filepath.Join(dir, snapshotManifest))
+	if os.IsNotExist(err) {
+		if entries, err = readJournal(dir); os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s holds no snapshot", dir)
+		}
+	} else if err == nil {
+		if err = json.Unmarshal(b, &entries); err != nil {
+			err = fmt.Errorf("%s: %v", filepath.Join(dir, snapshotManifest), err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results []RollbackResult
+	for _, e := range entries {
+		results = append(results, RollbackResult{e.Path, restoreFile(dir, e, force)})
+	}
+	return results, nil
+}
+
+// restoreFile restores the file of e from the snapshot in dir.
+func restoreFile(dir string, e SnapshotEntry, force bool) error {
+	cur, err := ioutil.ReadFile(e.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		switch hashContents(cur) {
+		case e.Before:
+			return nil
+		case e.After:
+		default:
+			if !force {
+				return ErrSnapshotConflict
+			}
+		}
+	}
+	old, err := ioutil.ReadFile(filepath.Join(dir, e.Saved))
+	if err != nil {
+		return err
+	}
+	if hashContents(old) != e.Before {
+		return fmt.Errorf("saved copy %s is corrupted", e.Saved)
+	}
+	return replaceFile(e.Path, e.Mode, func(w io.Writer) error {
+		_, err := w.Write(old)
+		return err
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRollback(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	originals := map[string]string{
+		"a.go": "package a\n",
+		"b.go": "package b\n",
+	}
+	for name, content := range originals {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snap := filepath.Join(dir, "snapshot")
+	opts := Options{
+		Roots:    []string{dir},
+		Holder:   "Acme",
+		Year:     "2020",
+		License:  "MIT",
+		Snapshot: snap,
+		Logger:   log.New(ioutil.Discard, "", 0),
+	}
+	report, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(report.Results); n != 2 {
+		t.Fatalf("processed %d files, want 2 without the snapshot: %v", n, report.Results)
+	}
+	if _, err := Run(context.Background(), opts); err == nil {
+		t.Error("Run overwrote an existing snapshot")
+	}
+
+	// b.go is edited after the run
+	edited := filepath.Join(dir, "b.go")
+	if err := ioutil.WriteFile(edited, []byte("package edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results, err := Rollback(snap, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range results {
+		want := error(nil)
+		if res.Path == edited {
+			want = ErrSnapshotConflict
+		}
+		if res.Err != want {
+			t.Errorf("rollback of %s: got error %v, want %v", res.Path, res.Err, want)
+		}
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "a.go")); string(b) != originals["a.go"] {
+		t.Errorf("a.go not restored: %q", b)
+	}
+	if b, _ := ioutil.ReadFile(edited); string(b) != "package edited\n" {
+		t.Errorf("rollback overwrote an edited file: %q", b)
+	}
+
+	if _, err := Rollback(snap, true); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(edited); string(b) != originals["b.go"] {
+		t.Errorf("forced rollback didn't restore b.go: %q", b)
+	}
+}
+
+func TestSnapshotHeaderAndFooter(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.c")
+	const original = "int a;\n"
+	if err := ioutil.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	snap := filepath.Join(dir, "snapshot")
+	opts := Options{
+		Roots:    []string{path},
+		Holder:   "Acme",
+		Year:     "2020",
+		License:  "MIT",
+		Footers:  map[string]string{"c": "END OF FILE"},
+		Snapshot: snap,
+		Logger:   log.New(ioutil.Discard, "", 0),
+	}
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "Acme") || !strings.Contains(string(b), "END OF FILE") {
+		t.Fatalf("run didn't add both the header and the footer: %q", b)
+	}
+	if _, err := os.Stat(filepath.Join(snap, snapshotJournal)); !os.IsNotExist(err) {
+		t.Errorf("journal left once the manifest is written: %v", err)
+	}
+
+	results, err := Rollback(snap, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Err != nil {
+		t.Errorf("rollback returned %v, want a single restored file", results)
+	}
+	if b, _ := ioutil.ReadFile(path); string(b) != original {
+		t.Errorf("a.c not restored: %q", b)
+	}
+}
+
+func TestRollbackJournal(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	originals := map[string]string{
+		filepath.Join(dir, "a.go"): "package a\n",
+		filepath.Join(dir, "b.go"): "package b\n",
+	}
+	for path, content := range originals {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snap := filepath.Join(dir, "snapshot")
+	r, err := newRunner(Options{License: "MIT", Holder: "Acme", Snapshot: snap, Logger: log.New(ioutil.Discard, "", 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the run is killed after updating the files, before writing the manifest
+	a := filepath.Join(dir, "a.go")
+	for _, content := range []string{"// header\npackage a\n", "// header\npackage a\n// footer\n"} {
+		if err := r.writeFile(a, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.writeFile(filepath.Join(dir, "b.go"), []byte("// header\npackage b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// with an entry cut short
+	if _, err := r.snapshot.journal.WriteString(`{"path":"`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(snap, snapshotManifest)); !os.IsNotExist(err) {
+		t.Fatalf("manifest written before the end of the run: %v", err)
+	}
+
+	results, err := Rollback(snap, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(originals) {
+		t.Errorf("rollback returned %v, want one result per file", results)
+	}
+	for _, res := range results {
+		if res.Err != nil {
+			t.Errorf("rollback of %s: %v", res.Path, res.Err)
+		}
+	}
+	for path, content := range originals {
+		if b, _ := ioutil.ReadFile(path); string(b) != content {
+			t.Errorf("%s not restored: %q", path, b)
+		}
+	}
+}
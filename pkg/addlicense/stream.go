@@ -0,0 +1,135 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// streamThreshold is the size above which files missing a license header are
+// updated by streaming their contents after the header into a temporary file,
+// instead of being read whole into memory.
+var streamThreshold int64 = 8 << 20
+
+// streamHead is the number of bytes read from the start of streamed files to
+// look for license markers, a hashbang line or a notebook preamble.
+const streamHead = 64 << 10
+
+// canStream reports whether the file at path, of the given size, is updated
+// by streaming it. Dry runs, snapshots, backups, output directories and Go
+// verification need the whole contents, as do unsaved buffers, which aren't
+// on disk.
+func (r *runner) canStream(path string, size int64) bool {
+	if size <= streamThreshold || r.opts.DryRun || r.snapshot != nil || r.backups != nil || r.outputDir != "" || r.opts.VerifyGo && isGoFile(path) {
+		return false
+	}
+	_, ok := r.sources[path]
+	return !ok
+}
+
+// streamLicense adds a license header to the file at path if missing, like
+// addLicense, holding only its first streamHead bytes in memory. The updated
+// contents are written to a temporary file in the same directory, which then
+// replaces the file. Files that must be written in place, such as hard links,
+// are read whole first, since writing truncates them.
+//
+// It returns true if the file was updated.
+func (r *runner) streamLicense(path string, fmode os.FileMode) (bool, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+	head := make([]byte, streamHead)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	head = head[:n]
+	if r.hasLicense(head) {
+		return false, nil
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+	if generated, err := readerIsGenerated(src); err != nil || generated {
+		return false, err
+	}
+	lic, err := r.licenseHeader(path)
+	if err != nil || lic == nil {
+		return false, err
+	}
+	line := r.leadingLines(path, head)
+	lic = prependLines(line, lic)
+	if _, err := src.Seek(int64(len(line)), io.SeekStart); err != nil {
+		return false, err
+	}
+
+	if err := r.claimWrite(path); err != nil {
+		return false, err
+	}
+	start := time.Now()
+	err = renameOver(path, fmode, func(w io.Writer) error {
+		if _, err := w.Write(lic); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, src)
+		return err
+	})
+	if err == errInPlace {
+		var rest []byte
+		if rest, err = ioutil.ReadAll(src); err == nil {
+			err = writeInPlace(path, fmode, func(w io.Writer) error {
+				if _, err := w.Write(lic); err != nil {
+					return err
+				}
+				_, err := w.Write(rest)
+				return err
+			})
+		}
+	}
+	r.wrote(path, start)
+	return err == nil, err
+}
+
+// readerIsGenerated reports whether the contents of rd mark a generated file,
+// like isGenerated, reading them a line at a time. Lines longer than the
+// read buffer can't be markers and are skipped.
+func readerIsGenerated(rd io.Reader) (bool, error) {
+	br := bufio.NewReaderSize(rd, streamHead)
+	long := false
+	for {
+		line, err := br.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			long = true
+			continue
+		}
+		if !long && isGenerated(bytes.TrimSuffix(line, []byte("\n"))) {
+			return true, nil
+		}
+		long = false
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
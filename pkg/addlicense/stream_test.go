@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStreamLicense(t *testing.T) {
+	big := strings.Repeat("// filler line\n", 10000)
+	files := map[string]string{
+		"a.go":         "package a\n" + big,
+		"b.sh":         "#!/bin/sh\necho b\n" + strings.Repeat("# x\n", 30000),
+		"c.go":         "// Copyright 2020 Someone\n\npackage c\n" + big,
+		"generated.go": "package g\n" + big + "// Code generated by x. DO NOT EDIT.\n",
+		"small.go":     "package s\n",
+	}
+	run := func(threshold int64) map[string]string {
+		defer func(old int64) { streamThreshold = old }(streamThreshold)
+		streamThreshold = threshold
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		for name, content := range files {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0640); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := Run(context.Background(), Options{
+			Roots:   []string{dir},
+			License: "MIT",
+			Holder:  "Google LLC",
+			Year:    "2026",
+			Logger:  log.New(ioutil.Discard, "", 0),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]string)
+		for name := range files {
+			path := filepath.Join(dir, name)
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[name] = string(b)
+			fi, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fi.Mode().Perm() != 0640 {
+				t.Errorf("%s has mode %v, want 0640", name, fi.Mode().Perm())
+			}
+		}
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != len(files) {
+			t.Errorf("%d files left in the directory, want %d", len(entries), len(files))
+		}
+		return got
+	}
+
+	want := run(1 << 62)
+	got := run(1024)
+	for name := range files {
+		if got[name] != want[name] {
+			t.Errorf("streamed %s differs:\n%.200q\nwant:\n%.200q", name, got[name], want[name])
+		}
+	}
+	if !strings.HasPrefix(want["b.sh"], "#!/bin/sh\n# Copyright") {
+		t.Errorf("b.sh has no license header below its hashbang line:\n%.200q", want["b.sh"])
+	}
+	if want["a.go"] == files["a.go"] {
+		t.Error("a.go wasn't modified")
+	}
+	for _, name := range []string{"c.go", "generated.go"} {
+		if want[name] != files[name] {
+			t.Errorf("%s was modified", name)
+		}
+	}
+}
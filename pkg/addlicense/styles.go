@@ -0,0 +1,189 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CommentStyleNames returns the names of the comment styles, which assign
+// comment styles to extensions in Options.ExtStyles.
+func CommentStyleNames() []string {
+	names := make([]string, len(commentStyles))
+	for i, s := range commentStyles {
+		names[i] = s.name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupCommentStyle returns the comment style named name.
+func lookupCommentStyle(name string) (*commentStyle, error) {
+	for _, s := range commentStyles {
+		if s.name == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown comment style %q, expected one of: %s", name, strings.Join(CommentStyleNames(), ", "))
+}
+
+// parseExtStyles returns the comment styles of exts, which maps file
+// extensions, or names of files without extension, to comment style names.
+func parseExtStyles(exts map[string]string) (map[string]*commentStyle, error) {
+	styles := make(map[string]*commentStyle)
+	for ext, name := range exts {
+		s, err := lookupCommentStyle(name)
+		if err != nil {
+			return nil, fmt.Errorf("extension %q: %w", ext, err)
+		}
+		styles[strings.ToLower(strings.TrimPrefix(ext, "."))] = s
+	}
+	return styles, nil
+}
+
+// parseCommentStyle parses the definition def of a custom comment style:
+// either the prefix of line comments, such as "# ", or the comma-separated
+// start, line prefix and end of block comments, such as "/*, * , */". The
+// parts are kept verbatim, spaces included.
+func parseCommentStyle(def string) (*commentStyle, error) {
+	parts := strings.Split(def, ",")
+	switch {
+	case len(parts) == 1 && strings.TrimSpace(def) != "":
+		return &commentStyle{name: "custom", mid: def}, nil
+	case len(parts) == 3 && strings.TrimSpace(parts[0]) != "" && strings.TrimSpace(parts[2]) != "":
+		return &commentStyle{name: "custom", top: parts[0], mid: parts[1], bot: parts[2]}, nil
+	}
+	return nil, fmt.Errorf("comment style %q: expected a line comment prefix, or the start, line prefix and end of block comments separated by commas", def)
+}
+
+// parseCommentStyles adds to styles the custom comment styles of defs, which
+// maps file extensions, or names of files without extension, to their
+// definitions, see parseCommentStyle.
+func parseCommentStyles(styles map[string]*commentStyle, defs map[string]string) error {
+	for ext, def := range defs {
+		s, err := parseCommentStyle(def)
+		if err != nil {
+			return fmt.Errorf("extension %q: %w", ext, err)
+		}
+		styles[strings.ToLower(strings.TrimPrefix(ext, "."))] = s
+	}
+	return nil
+}
+
+// extKey returns the key of the file at path in extension maps: its
+// lowercase extension without the dot, or its name if it has no extension.
+func extKey(path string) string {
+	return strings.TrimPrefix(fileExtension(strings.ToLower(filepath.Base(path))), ".")
+}
+
+// style returns the comment style of the file at path: the one assigned to
+// its extension by the run, if any, or the built-in one.
+func (r *runner) style(path string) *commentStyle {
+	ext := extKey(path)
+	if s, ok := r.styles[ext]; ok {
+		return s
+	}
+	if sniff, ok := sniffedStyles[ext]; ok {
+		return r.sniffStyle(path, sniff)
+	}
+	return fileCommentStyle(path)
+}
+
+// styleSniffer tells apart the files of an extension shared by languages of
+// different comment styles: those whose contents, or their start, match get
+// style rather than the built-in one.
+type styleSniffer struct {
+	match func(b []byte) bool
+	style *commentStyle
+}
+
+// sniffedStyles maps extensions shared by languages of different comment
+// styles to their sniffers.
+var sniffedStyles = map[string]styleSniffer{
+	"m":  {isMATLAB, stylePercent},
+	"pp": {isPascal, stylePascal},
+}
+
+// sniffStyle returns the comment style of the file at path, told by sniff.
+// The style of each file is decided once per run, from its contents before
+// the run.
+func (r *runner) sniffStyle(path string, sniff styleSniffer) *commentStyle {
+	r.mu.Lock()
+	s, ok := r.sniffed[path]
+	r.mu.Unlock()
+	if ok {
+		return s
+	}
+	s = fileCommentStyle(path)
+	if b, err := r.readHead(path); err == nil && sniff.match(b) {
+		s = sniff.style
+	}
+	r.mu.Lock()
+	if r.sniffed == nil {
+		r.sniffed = make(map[string]*commentStyle)
+	}
+	r.sniffed[path] = s
+	r.mu.Unlock()
+	return s
+}
+
+// suggestedStyles maps extensions of languages without built-in support to
+// the name of the comment style they most likely use.
+var suggestedStyles = map[string]string{
+	"agda":    "dash",
+	"bash":    "hash",
+	"clj":     "lisp",
+	"cljs":    "lisp",
+	"coffee":  "hash",
+	"cr":      "hash",
+	"cts":     "jsdoc",
+	"cue":     "slash",
+	"elm":     "dash",
+	"ex":      "hash",
+	"exs":     "hash",
+	"fish":    "hash",
+	"fs":      "slash",
+	"fsx":     "slash",
+	"gql":     "hash",
+	"gradle":  "slash",
+	"graphql": "hash",
+	"idr":     "dash",
+	"jsonnet": "slash",
+	"less":    "jsdoc",
+	"mts":     "jsdoc",
+	"nim":     "hash",
+	"nix":     "hash",
+	"purs":    "dash",
+	"rkt":     "lisp",
+	"scm":     "lisp",
+	"sml":     "ocaml",
+	"vhd":     "dash",
+	"vhdl":    "dash",
+	"xsd":     "html",
+	"xsl":     "html",
+	"xslt":    "html",
+	"zig":     "slash",
+	"zsh":     "hash",
+}
+
+// SuggestCommentStyle returns the name of the comment style most likely used
+// by the files with extension ext, as reported by Report.UnknownExtensions,
+// or an empty string if unknown.
+func SuggestCommentStyle(ext string) string {
+	return suggestedStyles[strings.ToLower(strings.TrimPrefix(ext, "."))]
+}
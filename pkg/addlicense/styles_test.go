@@ -0,0 +1,204 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtStyles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{
+		"main.elm":  "main = text \"1\"\n",
+		"data.json": "{}\n",
+		"flake.nix": "{ }\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := Run(context.Background(), Options{
+		Roots:     []string{dir},
+		Holder:    "Acme",
+		Year:      "2020",
+		License:   "MIT",
+		SPDX:      SPDXOnly,
+		ExtStyles: map[string]string{".ELM": "dash"},
+		Logger:    log.New(ioutil.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "main.elm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "-- Copyright 2020 Acme\n-- SPDX-License-Identifier: MIT\n\nmain = text \"1\"\n"; string(b) != want {
+		t.Errorf("main.elm is %q, want %q", b, want)
+	}
+	if got, want := report.UnknownExtensions(), map[string]int{"json": 1, "nix": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnknownExtensions returned %v, want %v", got, want)
+	}
+
+	if _, err := Run(context.Background(), Options{Roots: []string{dir}, License: "MIT", ExtStyles: map[string]string{"lua": "lua"}}); err == nil {
+		t.Error("Run with an unknown comment style returned no error")
+	}
+}
+
+func TestParseCommentStyle(t *testing.T) {
+	tests := []struct {
+		def  string
+		want *commentStyle
+	}{
+		{"# ", &commentStyle{"custom", "", "# ", ""}},
+		{"/*, * ,*/", &commentStyle{"custom", "/*", " * ", "*/"}},
+		{"{-,,-}", &commentStyle{"custom", "{-", "", "-}"}},
+		{"", nil},
+		{" ", nil},
+		{"/*, * ", nil},
+		{", * ,*/", nil},
+	}
+	for _, tt := range tests {
+		got, err := parseCommentStyle(tt.def)
+		if !reflect.DeepEqual(got, tt.want) || (err == nil) != (tt.want != nil) {
+			t.Errorf("parseCommentStyle(%q) returned %+v, %v, want %+v", tt.def, got, err, tt.want)
+		}
+	}
+}
+
+func TestCommentStyles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{
+		"a.foo": "foo\n",
+		"a.bar": "bar\n",
+		"a.lua": "print(1)\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := Run(context.Background(), Options{
+		Roots:         []string{dir},
+		Holder:        "Acme",
+		Year:          "2020",
+		License:       "MIT",
+		SPDX:          SPDXOnly,
+		ExtStyles:     map[string]string{"lua": "dash"},
+		CommentStyles: map[string]string{".foo": "# ", ".bar": "/*, * , */", "lua": "--[[,  ,]]"},
+		Logger:        log.New(ioutil.Discard, "", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"a.foo": "# Copyright 2020 Acme\n# SPDX-License-Identifier: MIT\n\nfoo\n",
+		"a.bar": "/*\n * Copyright 2020 Acme\n * SPDX-License-Identifier: MIT\n */\n\nbar\n",
+		"a.lua": "--[[\n  Copyright 2020 Acme\n  SPDX-License-Identifier: MIT\n]]\n\nprint(1)\n",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s is %q, want %q", name, b, want)
+		}
+	}
+
+	if _, err := Run(context.Background(), Options{Roots: []string{dir}, License: "MIT", CommentStyles: map[string]string{"foo": "/*, */"}}); err == nil {
+		t.Error("Run with an invalid comment style returned no error")
+	}
+}
+
+func TestSuggestCommentStyle(t *testing.T) {
+	for _, name := range suggestedStyles {
+		if _, err := lookupCommentStyle(name); err != nil {
+			t.Error(err)
+		}
+	}
+	if got := SuggestCommentStyle(".Elm"); got != "dash" {
+		t.Errorf("SuggestCommentStyle(.Elm) returned %q, want dash", got)
+	}
+	if got := SuggestCommentStyle("json"); got != "" {
+		t.Errorf("SuggestCommentStyle(json) returned %q, want none", got)
+	}
+}
+
+func TestSniffedStyles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"fit.m":    "function y = fit(x)\n  y = 2 * x;\nend\n",
+		"run.m":    "% Runs the fit.\ndisp(fit(1));\n",
+		"View.m":   "#import \"View.h\"\n\n@implementation View\n@end\n",
+		"hello.pp": "program Hello;\nbegin\nend.\n",
+		"site.pp":  "class site {\n}\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := Options{
+		Roots:   []string{dir},
+		Holder:  "Acme",
+		Year:    "2020",
+		License: "MIT",
+		SPDX:    SPDXOnly,
+		Logger:  log.New(ioutil.Discard, "", 0),
+	}
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"fit.m":    "% Copyright 2020 Acme\n% SPDX-License-Identifier: MIT\n\n" + files["fit.m"],
+		"run.m":    "% Copyright 2020 Acme\n% SPDX-License-Identifier: MIT\n\n" + files["run.m"],
+		"View.m":   "// Copyright 2020 Acme\n// SPDX-License-Identifier: MIT\n\n" + files["View.m"],
+		"hello.pp": "{\n  Copyright 2020 Acme\n  SPDX-License-Identifier: MIT\n}\n\n" + files["hello.pp"],
+		"site.pp":  "# Copyright 2020 Acme\n# SPDX-License-Identifier: MIT\n\n" + files["site.pp"],
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s is %q, want %q", name, b, want)
+		}
+	}
+
+	// the headers are found again by later runs
+	opts.Remove = true
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s is %q after removing its header, want %q", name, b, want)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+package body Counters is
+   procedure Increment (Value : in out Natural) is
+   begin
+      Value := Value + 1;
+   end Increment;
+end Counters;
@@ -0,0 +1,9 @@
+with Ada.Text_IO;
+with Counters;
+
+procedure Main is
+   N : Natural := 0;
+begin
+   Counters.Increment (N);
+   Ada.Text_IO.Put_Line (Natural'Image (N));
+end Main;
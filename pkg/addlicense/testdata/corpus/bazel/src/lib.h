@@ -0,0 +1,6 @@
+#ifndef SRC_LIB_H_
+#define SRC_LIB_H_
+
+inline int lib() { return 0; }
+
+#endif  // SRC_LIB_H_
@@ -0,0 +1,3 @@
+#include "src/lib.h"
+
+int main() { return lib(); }
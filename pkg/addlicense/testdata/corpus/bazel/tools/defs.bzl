@@ -0,0 +1,2 @@
+def example(name):
+    native.genrule(name = name)
@@ -0,0 +1,3 @@
+scale01 <- function(x) {
+  (x - min(x)) / (max(x) - min(x))
+}
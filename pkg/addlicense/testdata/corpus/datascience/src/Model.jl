@@ -0,0 +1,12 @@
+module Model
+
+export fit
+
+"""
+    fit(xs, ys)
+
+Least squares fit of a line through the points.
+"""
+fit(xs, ys) = hcat(ones(length(xs)), xs) \ ys
+
+end
@@ -0,0 +1,6 @@
+	org	0x100
+	mov	dx, msg
+	mov	ah, 9
+	int	0x21
+	ret
+msg	db	"Hello$"
@@ -0,0 +1,4 @@
+      SUBROUTINE HALVE(X)
+      REAL X
+      X = X / 2.0
+      END
@@ -0,0 +1,9 @@
+module solver
+  implicit none
+contains
+  pure function step(x) result(y)
+    real, intent(in) :: x
+    real :: y
+    y = x / 2.0
+  end function step
+end module solver
@@ -0,0 +1,9 @@
+local player = require("src.player")
+
+function love.load()
+  player.load()
+end
+
+function love.update(dt)
+  player.update(dt)
+end
@@ -0,0 +1,12 @@
+--[[
+Player movement.
+]]
+local player = {x = 0, y = 0, speed = 120}
+
+function player.load() end
+
+function player.update(dt)
+  player.x = player.x + player.speed * dt
+end
+
+return player
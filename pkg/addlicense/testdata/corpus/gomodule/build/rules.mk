@@ -0,0 +1,2 @@
+GOFLAGS ?= -trimpath
+export GOFLAGS
@@ -0,0 +1,5 @@
+module example.com/tool
+
+go 1.16
+
+require github.com/dep/dep v1.0.0
@@ -0,0 +1,5 @@
+// Package sys runs the tool.
+package sys
+
+// Run runs the tool.
+func Run() {}
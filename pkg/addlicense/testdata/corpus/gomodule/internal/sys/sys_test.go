@@ -0,0 +1,7 @@
+package sys
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	Run()
+}
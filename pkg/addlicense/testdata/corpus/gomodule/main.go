@@ -0,0 +1,7 @@
+package main
+
+import "example.com/tool/internal/sys"
+
+func main() {
+	sys.Run()
+}
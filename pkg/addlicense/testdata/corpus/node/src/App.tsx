@@ -0,0 +1 @@
+export const App = () => <div>app</div>;
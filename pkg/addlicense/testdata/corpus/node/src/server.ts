@@ -0,0 +1,3 @@
+export function serve(port: number): void {
+  console.log(port);
+}
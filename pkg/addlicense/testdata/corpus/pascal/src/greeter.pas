@@ -0,0 +1,14 @@
+unit Greeter;
+
+interface
+
+procedure Greet(const Name: string);
+
+implementation
+
+procedure Greet(const Name: string);
+begin
+  WriteLn('Hello, ', Name);
+end;
+
+end.
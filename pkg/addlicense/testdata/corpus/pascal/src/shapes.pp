@@ -0,0 +1,18 @@
+{$mode objfpc}
+unit Shapes;
+
+interface
+
+type
+  TShape = class
+    class function Unit: TShape;
+  end;
+
+implementation
+
+class function TShape.Unit: TShape;
+begin
+  Result := TShape.Create;
+end;
+
+end.
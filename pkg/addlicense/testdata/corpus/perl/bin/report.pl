@@ -0,0 +1,7 @@
+#!/usr/bin/perl -w
+use strict;
+use warnings;
+
+use Acme::Report;
+
+print Acme::Report::summary(@ARGV), "\n";
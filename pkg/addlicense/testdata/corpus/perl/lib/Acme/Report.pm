@@ -0,0 +1,10 @@
+package Acme::Report;
+
+use strict;
+use warnings;
+
+sub summary {
+    return join ", ", @_;
+}
+
+1;
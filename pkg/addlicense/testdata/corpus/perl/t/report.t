@@ -0,0 +1,7 @@
+#!perl -T
+use strict;
+use Test::More tests => 1;
+
+use Acme::Report;
+
+is(Acme::Report::summary("a", "b"), "a, b");
@@ -0,0 +1,12 @@
+#!/usr/bin/env python3
+"""Command line interface."""
+
+import sys
+
+
+def main():
+    print(sys.argv)
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,5 @@
+from pkg import cli
+
+
+def test_main():
+    cli.main()
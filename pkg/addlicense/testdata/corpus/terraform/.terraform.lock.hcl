@@ -0,0 +1,3 @@
+provider "registry.terraform.io/hashicorp/null" {
+  version = "3.0.0"
+}
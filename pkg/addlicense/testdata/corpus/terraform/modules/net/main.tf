@@ -0,0 +1,3 @@
+variable "cidr" {}
+
+resource "null_resource" "net" {}
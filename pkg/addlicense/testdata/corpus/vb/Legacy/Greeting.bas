@@ -0,0 +1,6 @@
+Attribute VB_Name = "Greeting"
+Option Explicit
+
+Public Function Hello(ByVal Name As String) As String
+    Hello = "Hello, " & Name
+End Function
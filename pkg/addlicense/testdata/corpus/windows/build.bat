@@ -0,0 +1,7 @@
+@echo off
+setlocal
+if "%1"=="" (
+  echo usage: build target
+  exit /b 1
+)
+call scripts\setup.cmd
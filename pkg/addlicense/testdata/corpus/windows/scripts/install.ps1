@@ -0,0 +1,5 @@
+#Requires -Version 5.1
+param([string]$Target = "C:\Tools")
+
+Import-Module "$PSScriptRoot\..\Tools\Tools.psd1"
+Install-Tools -Target $Target
@@ -0,0 +1,2 @@
+REM Sets up the build environment.
+set PATH=%CD%\tools;%PATH%
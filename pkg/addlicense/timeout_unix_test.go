@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux || darwin
+// +build linux darwin
+
+package addlicense
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestSkipFIFO(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	if err := syscall.Mkfifo(filepath.Join(dir, "fifo.go"), 0644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	path := filepath.Join(dir, "file.go")
+	if err := ioutil.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Run(context.Background(), Options{
+		Roots:     []string{dir},
+		Holder:    "Acme",
+		License:   "MIT",
+		CheckOnly: true,
+		Logger:    log.New(ioutil.Discard, "", 0),
+	})
+	if err != ErrMissingLicense {
+		t.Fatalf("Run returned %v, want %v", err, ErrMissingLicense)
+	}
+	if len(report.Results) != 1 || report.Results[0].Path != path {
+		t.Errorf("Run reported %+v, want only %s", report.Results, path)
+	}
+}
+
+func TestFileTimeout(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fifo.go")
+	if err := syscall.Mkfifo(path, 0644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	// opening the FIFO for writing unblocks the reader left behind
+	defer func() {
+		if f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0); err == nil {
+			f.Close()
+		}
+	}()
+
+	r := &runner{
+		opts:    Options{CheckOnly: true, FileTimeout: 50 * time.Millisecond},
+		log:     log.New(ioutil.Discard, "", 0),
+		tmpl:    template.Must(template.New("").Parse(tmplMIT)),
+		markers: licenseMarkers,
+	}
+	status, err := r.updateFileTimeout(&file{path: path, mode: 0644, log: &fileLog{}})
+	if status != StatusError || err != ErrFileTimeout {
+		t.Errorf("updateFileTimeout returned %v, %v, want %v, %v", status, err, StatusError, ErrFileTimeout)
+	}
+}
+
+func TestFileTimeoutNoWrite(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fifo.go")
+	if err := syscall.Mkfifo(path, 0644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	defer func() {
+		if f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0); err == nil {
+			f.Close()
+		}
+	}()
+
+	r, err := newRunner(Options{Holder: "Acme", License: "MIT", FileTimeout: 50 * time.Millisecond, Logger: log.New(ioutil.Discard, "", 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := r.updateFileTimeout(&file{path: path, mode: 0644, log: &fileLog{}})
+	if status != StatusError || err != ErrFileTimeout {
+		t.Fatalf("updateFileTimeout returned %v, %v, want %v, %v", status, err, StatusError, ErrFileTimeout)
+	}
+
+	// the update left running in the background may still get to write it
+	if err := r.writeFile(path, []byte("// Copyright Acme\npackage main\n"), 0644); err != ErrFileTimeout {
+		t.Errorf("writeFile of a file timed out returned %v, want %v", err, ErrFileTimeout)
+	}
+	fi, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&os.ModeNamedPipe == 0 {
+		t.Error("file timed out was written")
+	}
+}
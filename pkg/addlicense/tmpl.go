@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package addlicense
 
 import (
 	"bufio"
@@ -29,31 +29,61 @@ var licenseTemplate = map[string]string{
 	"MIT":        tmplMIT,
 	"bsd":        tmplBSD,
 	"MPL-2.0":    tmplMPL,
+	"Unlicense":  tmplUnlicense,
+	"CC0-1.0":    tmplCC0,
 }
 
 // maintain backwards compatibility by mapping legacy license types to their
 // SPDX equivalents.
 var legacyLicenseTypes = map[string]string{
-	"apache": "Apache-2.0",
-	"mit":    "MIT",
-	"mpl":    "MPL-2.0",
+	"apache":    "Apache-2.0",
+	"mit":       "MIT",
+	"mpl":       "MPL-2.0",
+	"cc0":       "CC0-1.0",
+	"unlicense": "Unlicense",
 }
 
-// licenseData specifies the data used to fill out a license template.
-type licenseData struct {
+// SPDXMode controls whether license headers include an SPDX identifier.
+type SPDXMode string
+
+const (
+	SPDXOff   SPDXMode = ""      // no SPDX identifier
+	SPDXOn    SPDXMode = "true"  // SPDX identifier following the license text
+	SPDXOnly  SPDXMode = "only"  // SPDX identifier instead of the license text
+	SPDXTags  SPDXMode = "tags"  // SPDX file tags instead of the license text
+	SPDXShort SPDXMode = "short" // copyright statement and SPDX identifier on two lines
+)
+
+// LicenseData specifies the data used to fill out a license template.
+type LicenseData struct {
 	Year   string // Copyright year(s).
 	Holder string // Name of the copyright holder.
 	SPDXID string // SPDX Identifier
+
+	FileContributors []string // Values of SPDX-FileContributor tags.
+	FileType         string   // Value of the SPDX-FileType tag.
+
+	Notice string // Additional notice following the license text.
+}
+
+// FileCopyrightText returns the value of the SPDX-FileCopyrightText tag: the
+// copyright year(s), if any, followed by the holder.
+func (d LicenseData) FileCopyrightText() string {
+	return strings.TrimSpace(d.Year + " " + d.Holder)
 }
 
 // fetchTemplate returns the license template for the specified license and
 // optional templateFile. If templateFile is provided, the license is read
 // from the specified file. Otherwise, a template is loaded for the specified
 // license, if recognized.
-func fetchTemplate(license string, templateFile string, spdx spdxFlag) (string, error) {
+func fetchTemplate(license string, templateFile string, spdx SPDXMode) (string, error) {
 	var t string
-	if spdx == spdxOnly {
+	if spdx == SPDXOnly {
 		t = tmplSPDX
+	} else if spdx == SPDXTags {
+		t = tmplSPDXTags
+	} else if spdx == SPDXShort {
+		t = tmplShort
 	} else if templateFile != "" {
 		d, err := ioutil.ReadFile(templateFile)
 		if err != nil {
@@ -64,13 +94,13 @@ func fetchTemplate(license string, templateFile string, spdx spdxFlag) (string,
 	} else {
 		t = licenseTemplate[license]
 		if t == "" {
-			if spdx == spdxOn {
+			if spdx == SPDXOn {
 				// unknown license, but SPDX headers requested
 				t = tmplSPDX
 			} else {
 				return "", fmt.Errorf("unknown license: %q. Include the '-s' flag to request SPDX style headers using this license", license)
 			}
-		} else if spdx == spdxOn {
+		} else if spdx == SPDXOn {
 			// append spdx headers to recognized license
 			t = t + spdxSuffix
 		}
@@ -79,9 +109,22 @@ func fetchTemplate(license string, templateFile string, spdx spdxFlag) (string,
 	return t, nil
 }
 
+// noticeSuffix is appended to license templates that don't place the
+// additional notice themselves.
+const noticeSuffix = "\n\n{{.Notice}}"
+
+// withNotice returns the license template t with the additional notice
+// appended, unless t already includes it, either with {{.Notice}} or as is.
+func withNotice(t, notice string) string {
+	if notice == "" || strings.Contains(t, ".Notice") || strings.Contains(t, notice) {
+		return t
+	}
+	return t + noticeSuffix
+}
+
 // executeTemplate will execute a license template t with data d
 // and prefix the result with top, middle and bottom.
-func executeTemplate(t *template.Template, d licenseData, top, mid, bot string) ([]byte, error) {
+func executeTemplate(t *template.Template, d LicenseData, top, mid, bot string) ([]byte, error) {
 	var buf bytes.Buffer
 	if err := t.Execute(&buf, d); err != nil {
 		return nil, err
@@ -92,7 +135,11 @@ func executeTemplate(t *template.Template, d licenseData, top, mid, bot string)
 	}
 	s := bufio.NewScanner(&buf)
 	for s.Scan() {
-		fmt.Fprintln(&out, strings.TrimRightFunc(mid+s.Text(), unicode.IsSpace))
+		line := s.Text()
+		if d.Year == "" {
+			line = squeezeCopyright(line)
+		}
+		fmt.Fprintln(&out, strings.TrimRightFunc(mid+line, unicode.IsSpace))
 	}
 	if bot != "" {
 		fmt.Fprintln(&out, bot)
@@ -101,6 +148,17 @@ func executeTemplate(t *template.Template, d licenseData, top, mid, bot string)
 	return out.Bytes(), nil
 }
 
+// squeezeCopyright collapses the runs of spaces within a copyright statement
+// that are left behind by templates such as "Copyright {{.Year}} {{.Holder}}"
+// when no year is provided. Other lines are returned unchanged.
+func squeezeCopyright(line string) string {
+	if !strings.Contains(strings.ToLower(line), "copyright") {
+		return line
+	}
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	return line[:indent] + strings.Join(strings.Fields(line[indent:]), " ")
+}
+
 const tmplApache = `Copyright{{ if .Year }} {{.Year}}{{ end }} {{.Holder}}
 
 Licensed under the Apache License, Version 2.0 (the "License");
@@ -142,7 +200,33 @@ const tmplMPL = `This Source Code Form is subject to the terms of the Mozilla Pu
 License, v. 2.0. If a copy of the MPL was not distributed with this
 file, You can obtain one at https://mozilla.org/MPL/2.0/.`
 
+const tmplUnlicense = `This is free and unencumbered software released into the public domain.
+
+Anyone is free to copy, modify, publish, use, compile, sell, or distribute
+this software, either in source code form or as a compiled binary, for any
+purpose, commercial or non-commercial, and by any means.
+
+For more information, please refer to <https://unlicense.org/>`
+
+const tmplCC0 = `Written{{ if .Year }} in {{.Year}}{{ end }} by {{.Holder}}
+
+To the extent possible under law, the author(s) have dedicated all copyright
+and related and neighboring rights to this software to the public domain
+worldwide. This software is distributed without any warranty.
+
+You should have received a copy of the CC0 Public Domain Dedication along with
+this software. If not, see <https://creativecommons.org/publicdomain/zero/1.0/>.`
+
 const tmplSPDX = `{{ if .Holder }}Copyright{{ if .Year }} {{.Year}}{{ end }} {{.Holder}}
 {{ end }}SPDX-License-Identifier: {{.SPDXID}}`
 
+// tmplShort is the short built-in template: a copyright statement followed by
+// the SPDX identifier, the same two lines as SPDX only headers.
+const tmplShort = tmplSPDX
+
+const tmplSPDXTags = `{{ if .Holder }}SPDX-FileCopyrightText: {{.FileCopyrightText}}
+{{ end }}{{ range .FileContributors }}SPDX-FileContributor: {{.}}
+{{ end }}{{ if .FileType }}SPDX-FileType: {{.FileType}}
+{{ end }}SPDX-License-Identifier: {{.SPDXID}}`
+
 const spdxSuffix = "\n\nSPDX-License-Identifier: {{.SPDXID}}"
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package addlicense
 
 import (
 	"errors"
@@ -27,6 +27,8 @@ func init() {
 	template.Must(template.New("").Parse(tmplMIT))
 	template.Must(template.New("").Parse(tmplBSD))
 	template.Must(template.New("").Parse(tmplMPL))
+	template.Must(template.New("").Parse(tmplUnlicense))
+	template.Must(template.New("").Parse(tmplCC0))
 }
 
 func TestFetchTemplate(t *testing.T) {
@@ -34,7 +36,7 @@ func TestFetchTemplate(t *testing.T) {
 		description  string   // test case description
 		license      string   // license passed to fetchTemplate
 		templateFile string   // templatefile passed to fetchTemplate
-		spdx         spdxFlag // spdx value passed to fetchTemplate
+		spdx         SPDXMode // spdx value passed to fetchTemplate
 		wantTemplate string   // expected returned template
 		wantErr      error    // expected returned error
 	}{
@@ -43,7 +45,7 @@ func TestFetchTemplate(t *testing.T) {
 			"non-existent template file",
 			"",
 			"/does/not/exist",
-			spdxOff,
+			SPDXOff,
 			"",
 			os.ErrNotExist,
 		},
@@ -51,7 +53,7 @@ func TestFetchTemplate(t *testing.T) {
 			"custom template file",
 			"",
 			"testdata/custom.tpl",
-			spdxOff,
+			SPDXOff,
 			"Copyright {{.Year}} {{.Holder}}\n\nCustom License Template\n",
 			nil,
 		},
@@ -60,7 +62,7 @@ func TestFetchTemplate(t *testing.T) {
 			"unknown license",
 			"unknown",
 			"",
-			spdxOff,
+			SPDXOff,
 			"",
 			errors.New(`unknown license: "unknown". Include the '-s' flag to request SPDX style headers using this license`),
 		},
@@ -70,7 +72,7 @@ func TestFetchTemplate(t *testing.T) {
 			"apache license template",
 			"Apache-2.0",
 			"",
-			spdxOff,
+			SPDXOff,
 			tmplApache,
 			nil,
 		},
@@ -78,7 +80,7 @@ func TestFetchTemplate(t *testing.T) {
 			"mit license template",
 			"MIT",
 			"",
-			spdxOff,
+			SPDXOff,
 			tmplMIT,
 			nil,
 		},
@@ -86,7 +88,7 @@ func TestFetchTemplate(t *testing.T) {
 			"bsd license template",
 			"bsd",
 			"",
-			spdxOff,
+			SPDXOff,
 			tmplBSD,
 			nil,
 		},
@@ -94,17 +96,33 @@ func TestFetchTemplate(t *testing.T) {
 			"mpl license template",
 			"MPL-2.0",
 			"",
-			spdxOff,
+			SPDXOff,
 			tmplMPL,
 			nil,
 		},
+		{
+			"unlicense template",
+			"Unlicense",
+			"",
+			SPDXOff,
+			tmplUnlicense,
+			nil,
+		},
+		{
+			"cc0 template",
+			"CC0-1.0",
+			"",
+			SPDXOff,
+			tmplCC0,
+			nil,
+		},
 
 		// SPDX variants
 		{
 			"apache license template with SPDX added",
 			"Apache-2.0",
 			"",
-			spdxOn,
+			SPDXOn,
 			tmplApache + spdxSuffix,
 			nil,
 		},
@@ -112,7 +130,7 @@ func TestFetchTemplate(t *testing.T) {
 			"apache license template with SPDX only",
 			"Apache-2.0",
 			"",
-			spdxOnly,
+			SPDXOnly,
 			tmplSPDX,
 			nil,
 		},
@@ -120,10 +138,18 @@ func TestFetchTemplate(t *testing.T) {
 			"unknown license with SPDX only",
 			"unknown",
 			"",
-			spdxOnly,
+			SPDXOnly,
 			tmplSPDX,
 			nil,
 		},
+		{
+			"apache license template with SPDX tags",
+			"Apache-2.0",
+			"",
+			SPDXTags,
+			tmplSPDXTags,
+			nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -139,57 +165,99 @@ func TestFetchTemplate(t *testing.T) {
 	}
 }
 
+func TestWithNotice(t *testing.T) {
+	tests := []struct {
+		tmpl   string
+		notice string
+		want   string
+	}{
+		{tmplBSD, "", tmplBSD},
+		{tmplBSD, "Acme is a trademark of Acme Corp.", tmplBSD + noticeSuffix},
+		{"{{.Notice}}\n\nCopyright {{.Holder}}", "Acme is a trademark of Acme Corp.", "{{.Notice}}\n\nCopyright {{.Holder}}"},
+		{"Copyright {{.Holder}}\nAcme is a trademark of Acme Corp.", "Acme is a trademark of Acme Corp.", "Copyright {{.Holder}}\nAcme is a trademark of Acme Corp."},
+	}
+	for _, tt := range tests {
+		if got := withNotice(tt.tmpl, tt.notice); got != tt.want {
+			t.Errorf("withNotice(%q, %q) returned %q, want %q", tt.tmpl, tt.notice, got, tt.want)
+		}
+	}
+}
+
 func TestExecuteTemplate(t *testing.T) {
 	tests := []struct {
 		name          string
 		template      string
-		data          licenseData
+		data          LicenseData
 		top, mid, bot string
 		want          string
 	}{
 		{
 			"empty template",
 			"",
-			licenseData{},
+			LicenseData{},
 			"", "", "",
 			"\n",
 		},
 		{
 			"no extra",
 			"{{.Holder}}{{.Year}}{{.SPDXID}}",
-			licenseData{Holder: "H", Year: "Y", SPDXID: "S"},
+			LicenseData{Holder: "H", Year: "Y", SPDXID: "S"},
 			"", "", "",
 			"HYS\n\n",
 		},
 		{
 			"only mid",
 			"{{.Holder}}{{.Year}}{{.SPDXID}}",
-			licenseData{Holder: "H", Year: "Y", SPDXID: "S"},
+			LicenseData{Holder: "H", Year: "Y", SPDXID: "S"},
 			"", "// ", "",
 			"// HYS\n\n",
 		},
 		{
 			"top, mid, bot",
 			"{{.Holder}}{{.Year}}{{.SPDXID}}",
-			licenseData{Holder: "H", Year: "Y", SPDXID: "S"},
+			LicenseData{Holder: "H", Year: "Y", SPDXID: "S"},
 			"/*", " * ", "*/",
 			"/*\n * HYS\n*/\n\n",
 		},
 
+		{
+			"SPDX tags",
+			tmplSPDXTags,
+			LicenseData{Holder: "H", Year: "Y", SPDXID: "S", FileContributors: []string{"A", "B"}, FileType: "SOURCE"},
+			"", "// ", "",
+			"// SPDX-FileCopyrightText: Y H\n// SPDX-FileContributor: A\n// SPDX-FileContributor: B\n// SPDX-FileType: SOURCE\n// SPDX-License-Identifier: S\n\n",
+		},
+		{
+			"SPDX tags, no year",
+			tmplSPDXTags,
+			LicenseData{Holder: "H", SPDXID: "S"},
+			"", "# ", "",
+			"# SPDX-FileCopyrightText: H\n# SPDX-License-Identifier: S\n\n",
+		},
+
 		// ensure we don't escape HTML characters by using the wrong template package
 		{
 			"html chars",
 			"{{.Holder}}",
-			licenseData{Holder: "A&Z"},
+			LicenseData{Holder: "A&Z"},
 			"", "", "",
 			"A&Z\n\n",
 		},
 
+		// custom templates should not leave a dangling space without a year
+		{
+			"no year, custom template",
+			"Copyright {{.Year}} {{.Holder}}\n\n    Custom  License",
+			LicenseData{Holder: "Holder"},
+			"", "// ", "",
+			"// Copyright Holder\n//\n//     Custom  License\n\n",
+		},
+
 		// empty near should not add a space
 		{
 			"no year, apache",
 			tmplApache,
-			licenseData{Holder: "Holder"},
+			LicenseData{Holder: "Holder"},
 			"", "", "",
 			`Copyright Holder
 
@@ -210,7 +278,7 @@ limitations under the License.
 		{
 			"no year, BSD",
 			tmplBSD,
-			licenseData{Holder: "Holder"},
+			LicenseData{Holder: "Holder"},
 			"", "", "",
 			`Copyright (c) Holder All rights reserved.
 Use of this source code is governed by a BSD-style
@@ -221,7 +289,7 @@ license that can be found in the LICENSE file.
 		{
 			"no year, MIT",
 			tmplMIT,
-			licenseData{Holder: "Holder"},
+			LicenseData{Holder: "Holder"},
 			"", "", "",
 			`Copyright (c) Holder
 
@@ -247,7 +315,7 @@ CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 		{
 			"no year, SPDX",
 			tmplSPDX,
-			licenseData{Holder: "Holder", SPDXID: "Spdx"},
+			LicenseData{Holder: "Holder", SPDXID: "Spdx"},
 			"", "", "",
 			`Copyright Holder
 SPDX-License-Identifier: Spdx
@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HeaderVariant is a distinct license header text shared by files.
+type HeaderVariant struct {
+	// Text is the normalized header text: without comment markers, with runs
+	// of whitespace collapsed and copyright years replaced by "YEAR".
+	Text string
+	// Files lists the sorted paths of the files using the variant.
+	Files []string
+}
+
+// HeaderVariants walks roots and clusters the license headers of the files of
+// a known type found in them by normalized text, so that differences of
+// wording or holder stand out while differing years don't. Files matching an
+// ignore pattern are skipped. It returns the variants, the most used first,
+// and the sorted paths of the files without a license header.
+func HeaderVariants(roots, ignore []string) (variants []HeaderVariant, missing []string, err error) {
+	byText := make(map[string]*HeaderVariant)
+	for _, root := range roots {
+		err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if fi.IsDir() || fileMatches(path, ignore) || fileCommentStyle(path) == nil {
+				return nil
+			}
+			block, err := fileLicenseBlock(path)
+			if err != nil {
+				return err
+			}
+			if block == nil {
+				missing = append(missing, path)
+				return nil
+			}
+			text := normalizeHeader(fileCommentStyle(path), block)
+			v := byText[text]
+			if v == nil {
+				v = &HeaderVariant{Text: text}
+				byText[text] = v
+			}
+			v.Files = append(v.Files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, v := range byText {
+		sort.Strings(v.Files)
+		variants = append(variants, *v)
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		if len(variants[i].Files) != len(variants[j].Files) {
+			return len(variants[i].Files) > len(variants[j].Files)
+		}
+		return variants[i].Text < variants[j].Text
+	})
+	sort.Strings(missing)
+	return variants, missing, nil
+}
+
+// normalizeHeader returns the text of block, a license header in style,
+// without comment markers, with runs of whitespace collapsed and copyright
+// years replaced by "YEAR".
+func normalizeHeader(style *commentStyle, block []byte) string {
+	top := []byte(strings.TrimSpace(style.top))
+	mid := []byte(strings.TrimSpace(style.mid))
+	bot := []byte(strings.TrimSpace(style.bot))
+	var lines [][]byte
+	for off := 0; off < len(block); {
+		var line []byte
+		line, off = nextLine(block, off)
+		line = bytes.TrimSpace(line)
+		if len(top) > 0 {
+			line = bytes.TrimPrefix(line, top)
+		}
+		if len(bot) > 0 {
+			line = bytes.TrimSuffix(line, bot)
+		}
+		if len(mid) > 0 {
+			line = bytes.TrimPrefix(bytes.TrimSpace(line), mid)
+		}
+		lines = append(lines, line)
+	}
+	text := normalizeSpace(bytes.Join(lines, []byte(" ")))
+	return string(copyrightYears.ReplaceAll(text, []byte("${1}YEAR")))
+}
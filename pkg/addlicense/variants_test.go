@@ -0,0 +1,61 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHeaderVariants(t *testing.T) {
+	root := tempDir(t)
+	defer os.RemoveAll(root)
+	files := map[string]string{
+		"a.go":        "// Copyright 2020 Acme\n// Licensed under MIT.\n\npackage a\n",
+		"b.go":        "// Copyright 2018, 2019 Acme\n//   Licensed under MIT.\n\npackage b\n",
+		"c.py":        "#!/usr/bin/env python\n# Copyright 2021 Acme\n# Licensed under MIT.\n",
+		"d.c":         "/*\n * Copyright 2021 Acme Inc.\n * Licensed under MIT.\n */\n",
+		"e.go":        "package e\n",
+		"vendor/f.go": "// Copyright 2020 Other\n",
+		"g.txt":       "Copyright 2020 Other\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	variants, missing, err := HeaderVariants([]string{root}, []string{"**/vendor/**"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HeaderVariant{
+		{"Copyright YEAR Acme Licensed under MIT.", []string{filepath.Join(root, "a.go"), filepath.Join(root, "b.go"), filepath.Join(root, "c.py")}},
+		{"Copyright YEAR Acme Inc. Licensed under MIT.", []string{filepath.Join(root, "d.c")}},
+	}
+	if !reflect.DeepEqual(variants, want) {
+		t.Errorf("HeaderVariants returned %q, want %q", variants, want)
+	}
+	if want := []string{filepath.Join(root, "e.go")}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("HeaderVariants returned missing %q, want %q", missing, want)
+	}
+}
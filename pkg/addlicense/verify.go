@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// isGoFile reports whether path is a Go source file.
+func isGoFile(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".go"
+}
+
+// verifyGo verifies that updating the Go file at path from old to b keeps it
+// valid: b must parse, keep the build constraints of old in effect, and stay
+// gofmt formatted if old was.
+func verifyGo(path string, old, b []byte) error {
+	if _, err := parser.ParseFile(token.NewFileSet(), path, b, parser.ParseComments); err != nil {
+		return fmt.Errorf("update would break the Go syntax: %v", err)
+	}
+	if was, now := buildConstraints(old), buildConstraints(b); !reflect.DeepEqual(was, now) {
+		return fmt.Errorf("update would change the build constraints in effect from %q to %q", was, now)
+	}
+	if f, err := format.Source(old); err == nil && bytes.Equal(f, old) {
+		if f, err := format.Source(b); err != nil || !bytes.Equal(f, b) {
+			return fmt.Errorf("update would leave the file not gofmt formatted")
+		}
+	}
+	return nil
+}
+
+// buildConstraints returns the "//go:build" and "// +build" lines of the Go
+// source b that are in effect: those of the comment blocks preceding the
+// package clause that are followed by a blank line.
+func buildConstraints(b []byte) []string {
+	var found, block []string
+	for off := 0; off < len(b); {
+		var line []byte
+		line, off = nextLine(b, off)
+		line = bytes.TrimSpace(line)
+		switch {
+		case len(line) == 0:
+			// a blank line ends the block, putting its constraints in effect
+			found = append(found, block...)
+			block = nil
+		case bytes.HasPrefix(line, []byte("//")):
+			text := string(line)
+			if strings.HasPrefix(text, "//go:build ") || strings.HasPrefix(text, "// +build ") {
+				block = append(block, text)
+			}
+		default:
+			// constraints must precede any code or block comment
+			return found
+		}
+	}
+	return found
+}
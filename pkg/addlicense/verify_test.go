@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildConstraints(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"//go:build linux\n// +build linux\n\npackage a\n", []string{"//go:build linux", "// +build linux"}},
+		{"// Copyright 2020 Acme\n\n//go:build linux\n\npackage a\n", []string{"//go:build linux"}},
+		{"// Copyright 2020 Acme\n//go:build linux\n\npackage a\n", []string{"//go:build linux"}},
+
+		// constraints not followed by a blank line, or after code, are ignored
+		{"//go:build linux\npackage a\n", nil},
+		{"package a\n\n//go:build linux\n\n", nil},
+		{"/* Copyright */\n\n//go:build linux\n\npackage a\n", nil},
+	}
+	for _, tt := range tests {
+		if got := buildConstraints([]byte(tt.content)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("buildConstraints(%q) returned %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyGo(t *testing.T) {
+	tests := []struct {
+		old, new string
+		ok       bool
+	}{
+		{"//go:build linux\n\npackage a\n", "// Copyright 2020 Acme\n\n//go:build linux\n\npackage a\n", true},
+		{"package a\nvar x  = 1\n", "// Copyright 2020 Acme\n\npackage a\nvar x  = 1\n", true},
+
+		{"package a\n", "/* Copyright 2020 Acme\n\npackage a\n", false},
+		{"//go:build linux\n\npackage a\n", "//go:build linux\n// Copyright 2020 Acme\npackage a\n", false},
+		{"package a\n", "// Copyright 2020 Acme\n\n\n\npackage a\n", false},
+	}
+	for _, tt := range tests {
+		if err := verifyGo("a.go", []byte(tt.old), []byte(tt.new)); (err == nil) != tt.ok {
+			t.Errorf("verifyGo(%q, %q) returned %v, want ok %v", tt.old, tt.new, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// modeBits are the mode bits of a file that replaceFile preserves.
+const modeBits = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+
+// replaceFile replaces the file at path with the contents written by write.
+// They are written to a temporary file in the same directory, which is then
+// renamed over the original, so that a crash midway can't leave the file
+// truncated. The replacement keeps the owner, group and mode bits of the
+// original, or has the permissions fmode if there is none. Symbolic links are
+// followed and keep pointing to the replacement.
+//
+// Files with several hard links, files in directories that can't be written
+// and files whose owner can't be restored are written in place instead, since
+// renaming would split them from their other links or change their ownership.
+func replaceFile(path string, fmode os.FileMode, write func(io.Writer) error) error {
+	err := renameOver(path, fmode, write)
+	if err == errInPlace {
+		return writeInPlace(path, fmode, write)
+	}
+	return err
+}
+
+// errInPlace is the error of renameOver for files that must be written in
+// place.
+var errInPlace = errors.New("file must be written in place")
+
+// renameOver replaces the file at path like replaceFile, but fails with
+// errInPlace, before calling write, instead of writing it in place. Callers
+// reading the original contents while writing them can't have it truncated
+// first.
+func renameOver(path string, fmode os.FileMode, write func(io.Writer) error) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	fi, err := os.Stat(path)
+	if err == nil {
+		// read-only files stay read-only, although renaming could replace them
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		f.Close()
+		fmode = fi.Mode()
+		if _, ok := linkID(fi); ok {
+			return errInPlace
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if os.IsPermission(err) {
+		return errInPlace
+	}
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails once renamed
+	if fi != nil {
+		if err := chown(tmp, fi); err != nil {
+			tmp.Close()
+			return errInPlace
+		}
+	}
+	w := bufio.NewWriter(tmp)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		// after chown, which clears the setuid and setgid bits
+		err = tmp.Chmod(fmode & modeBits)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// writeInPlace truncates the file at path, or creates it with the permissions
+// fmode, and writes the contents written by write to it.
+func writeInPlace(path string, fmode os.FileMode, write func(io.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fmode.Perm())
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// mirrorPath returns the path of the copy of the file at path in dir, a
+// directory mirroring the tree of the files of a run: its path relative to
+// the current directory, or its absolute path if it is outside of it.
+func mirrorPath(dir, path string) string {
+	abs := canonicalPath(path)
+	if wd, err := os.Getwd(); err == nil {
+		rel, err := filepath.Rel(wd, abs)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.Join(dir, rel)
+		}
+	}
+	abs = abs[len(filepath.VolumeName(abs)):]
+	return filepath.Join(dir, abs)
+}
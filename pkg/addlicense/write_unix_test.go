@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package addlicense
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// writeString returns a write function of replaceFile writing s.
+func writeString(s string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "run.sh")
+	if err := ioutil.WriteFile(path, []byte("echo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mode := 0751 | os.ModeSetgid
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.sh")
+	if err := os.Symlink("run.sh", link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := replaceFile(link, 0644, writeString("# header\necho\n")); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "# header\necho\n"; got != want {
+		t.Errorf("replaced file holds %q, want %q", got, want)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fi.Mode(); got != mode {
+		t.Errorf("replaced file has mode %v, want %v", got, mode)
+	}
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("symbolic link was replaced: %v, %v", fi, err)
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("directory holds %d files, want 2: temporary files left behind", len(entries))
+	}
+}
+
+func TestReplaceFileOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing the owner of files requires root")
+	}
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.go")
+	if err := ioutil.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chown(path, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := replaceFile(path, 0644, writeString("// header\npackage a\n")); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := fi.Sys().(*syscall.Stat_t)
+	if st.Uid != 1 || st.Gid != 2 {
+		t.Errorf("replaced file is owned by %d:%d, want 1:2", st.Uid, st.Gid)
+	}
+}
+
+func TestReplaceFileReadOnly(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write read-only files")
+	}
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.go")
+	if err := ioutil.WriteFile(path, []byte("package a\n"), 0444); err != nil {
+		t.Fatal(err)
+	}
+	if err := replaceFile(path, 0644, writeString("// header\npackage a\n")); !os.IsPermission(err) {
+		t.Errorf("replaceFile returned %v, want a permission error", err)
+	}
+}
+
+func TestReplaceFileHardLink(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.go")
+	if err := ioutil.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(dir, "b.go")
+	if err := os.Link(path, other); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	if err := replaceFile(path, 0644, writeString("// header\npackage a\n")); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "// header\npackage a\n"; got != want {
+		t.Errorf("hard link holds %q, want %q", got, want)
+	}
+}
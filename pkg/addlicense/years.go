@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// YearPolicy defines how the years of existing copyright statements are
+// rewritten.
+type YearPolicy string
+
+const (
+	YearsKeep         YearPolicy = ""
+	YearsRanges       YearPolicy = "ranges"        // collapse consecutive years into ranges
+	YearsFirstCurrent YearPolicy = "first-current" // first year up to the current year
+)
+
+// copyrightYears matches the list of years that follows "Copyright" or
+// "Copyright (c)", such as "2015, 2016, 2018" or "2015-2017,2019".
+var copyrightYears = regexp.MustCompile(`(?i)(copyright(?:text:)?(?:\s*\(c\)|\s*©)?\s+)(\d{4}(?:\s*[,\-–]\s*\d{4})*)`)
+
+// normalizeYears rewrites the copyright years of the license header of the
+// file at path according to policy, with current being the current year.
+//
+// It returns true if the file was updated.
+func (r *runner) normalizeYears(path string, fmode os.FileMode, policy YearPolicy, current int) (bool, error) {
+	b, err := r.readFile(path)
+	if err != nil {
+		return false, err
+	}
+	nb := normalizeYearsIn(b, policy, current)
+	if string(nb) == string(b) {
+		return false, nil
+	}
+	return true, r.writeFile(path, nb, fmode)
+}
+
+// normalizeYearsIn returns b with the years of all copyright statements found
+// in its first 1000 bytes rewritten according to policy. Statements without a
+// year, or with a year list that cannot be parsed, are left untouched.
+func normalizeYearsIn(b []byte, policy YearPolicy, current int) []byte {
+	if policy == YearsKeep {
+		return b
+	}
+	n := 1000
+	if len(b) < n {
+		n = len(b)
+	}
+	head := copyrightYears.ReplaceAllFunc(b[:n], func(m []byte) []byte {
+		sub := copyrightYears.FindSubmatch(m)
+		years, ok := parseYears(string(sub[2]))
+		if !ok {
+			return m
+		}
+		var s string
+		switch policy {
+		case YearsRanges:
+			s = formatYearRanges(years)
+		case YearsFirstCurrent:
+			s = strconv.Itoa(years[0])
+			if years[0] < current {
+				s = fmt.Sprintf("%d-%d", years[0], current)
+			}
+		}
+		return append(sub[1], s...)
+	})
+	return append(head, b[n:]...)
+}
+
+// parseYears parses a comma separated list of years and year ranges, and
+// returns the sorted set of years it covers.
+func parseYears(s string) ([]int, bool) {
+	seen := make(map[int]bool)
+	var years []int
+	add := func(y int) {
+		if !seen[y] {
+			seen[y] = true
+			years = append(years, y)
+		}
+	}
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.FieldsFunc(part, func(r rune) bool { return r == '-' || r == '–' })
+		if len(bounds) == 0 || len(bounds) > 2 {
+			return nil, false
+		}
+		first, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, false
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(strings.TrimSpace(bounds[1])); err != nil || last < first {
+				return nil, false
+			}
+		}
+		for y := first; y <= last; y++ {
+			add(y)
+		}
+	}
+	sort.Ints(years)
+	return years, len(years) > 0
+}
+
+// formatYearRanges formats sorted years, collapsing consecutive years into
+// ranges, for example "2015-2017, 2019".
+func formatYearRanges(years []int) string {
+	var parts []string
+	for i := 0; i < len(years); {
+		j := i
+		for j+1 < len(years) && years[j+1] == years[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(years[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", years[i], years[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addlicense
+
+import "testing"
+
+func TestNormalizeYearsIn(t *testing.T) {
+	tests := []struct {
+		content string
+		policy  YearPolicy
+		want    string
+	}{
+		{"// Copyright 2015, 2016, 2017, 2019 Acme\n", YearsKeep, "// Copyright 2015, 2016, 2017, 2019 Acme\n"},
+
+		{"// Copyright 2015, 2016, 2017, 2019 Acme\n", YearsRanges, "// Copyright 2015-2017, 2019 Acme\n"},
+		{"// Copyright 2015-2017,2019 Acme\n", YearsRanges, "// Copyright 2015-2017, 2019 Acme\n"},
+		{"// Copyright (c) 2016, 2015, 2015-2016 Acme\n", YearsRanges, "// Copyright (c) 2015-2016 Acme\n"},
+		{"# Copyright 2019 Acme\n", YearsRanges, "# Copyright 2019 Acme\n"},
+		{"# COPYRIGHT 2018,2019 Acme\n", YearsRanges, "# COPYRIGHT 2018-2019 Acme\n"},
+
+		{"// SPDX-FileCopyrightText: 2018, 2019 Acme\n", YearsRanges, "// SPDX-FileCopyrightText: 2018-2019 Acme\n"},
+
+		{"// Copyright 2015, 2016, 2019 Acme\n", YearsFirstCurrent, "// Copyright 2015-2026 Acme\n"},
+		{"// Copyright 2026 Acme\n", YearsFirstCurrent, "// Copyright 2026 Acme\n"},
+
+		// statements without years or with unparsable ones are left untouched
+		{"// Copyright The Kubernetes Authors.\n", YearsRanges, "// Copyright The Kubernetes Authors.\n"},
+		{"// Copyright 2019-2015 Acme\n", YearsFirstCurrent, "// Copyright 2019-2015 Acme\n"},
+		{"// This is my license\n", YearsFirstCurrent, "// This is my license\n"},
+	}
+
+	for _, tt := range tests {
+		if got := string(normalizeYearsIn([]byte(tt.content), tt.policy, 2026)); got != tt.want {
+			t.Errorf("normalizeYearsIn(%q, %q) returned %q, want %q", tt.content, tt.policy, got, tt.want)
+		}
+	}
+}
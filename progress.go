@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// Intervals between the progress updates of -progress, on a terminal and
+// otherwise, such as in CI logs.
+const (
+	progressIntervalTTY = 200 * time.Millisecond
+	progressInterval    = 10 * time.Second
+)
+
+// progressPrinter writes the progress of a run to w: on a terminal, as a
+// status line rewritten in place, above which the lines logged meanwhile are
+// written, and otherwise as one line per update.
+type progressPrinter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	tty  bool
+	line string // status line shown on the terminal, if any
+}
+
+// update writes the progress p.
+func (pp *progressPrinter) update(p addlicense.Progress) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	line := formatProgress(p, pp.tty)
+	if !pp.tty {
+		fmt.Fprintln(pp.w, line)
+		return
+	}
+	pp.line = line
+	fmt.Fprint(pp.w, "\r\x1b[K"+line)
+}
+
+// Write writes the log lines b, above the status line on a terminal.
+func (pp *progressPrinter) Write(b []byte) (int, error) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	if !pp.tty || pp.line == "" {
+		return pp.w.Write(b)
+	}
+	if _, err := fmt.Fprint(pp.w, "\r\x1b[K"); err != nil {
+		return 0, err
+	}
+	n, err := pp.w.Write(b)
+	if err == nil {
+		_, err = fmt.Fprint(pp.w, pp.line)
+	}
+	return n, err
+}
+
+// done ends the status line on a terminal, so that the output following the
+// run starts on a line of its own.
+func (pp *progressPrinter) done() {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	if pp.tty && pp.line != "" {
+		fmt.Fprintln(pp.w)
+		pp.line = ""
+	}
+}
+
+// formatProgress returns the line describing the progress p, preceded by a bar
+// if bar is true and the total number of files is known.
+func formatProgress(p addlicense.Progress, bar bool) string {
+	elapsed := p.Elapsed.Round(time.Second)
+	if !p.WalkDone {
+		return fmt.Sprintf("progress: %d files processed of %d found so far, %v", p.Processed, p.Found, elapsed)
+	}
+	percent := 100
+	if p.Found > 0 {
+		percent = p.Processed * 100 / p.Found
+	}
+	line := fmt.Sprintf("progress: %d/%d files processed (%d%%), %v", p.Processed, p.Found, percent, elapsed)
+	if bar {
+		line = fmt.Sprintf("[%-20s] %s", strings.Repeat("#", percent/5), line)
+	}
+	return line
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestFormatProgress(t *testing.T) {
+	tests := []struct {
+		p    addlicense.Progress
+		bar  bool
+		want string
+	}{
+		{addlicense.Progress{Found: 120, Processed: 80, Elapsed: 3200 * time.Millisecond}, true, "progress: 80 files processed of 120 found so far, 3s"},
+		{addlicense.Progress{Found: 200, Processed: 50, WalkDone: true, Elapsed: time.Minute}, false, "progress: 50/200 files processed (25%), 1m0s"},
+		{addlicense.Progress{Found: 200, Processed: 50, WalkDone: true, Elapsed: time.Minute}, true, "[#####               ] progress: 50/200 files processed (25%), 1m0s"},
+		{addlicense.Progress{WalkDone: true}, false, "progress: 0/0 files processed (100%), 0s"},
+	}
+	for _, tt := range tests {
+		if got := formatProgress(tt.p, tt.bar); got != tt.want {
+			t.Errorf("formatProgress(%+v, %v) = %q, want %q", tt.p, tt.bar, got, tt.want)
+		}
+	}
+}
+
+func TestProgressPrinter(t *testing.T) {
+	var out strings.Builder
+	pp := &progressPrinter{w: &out, tty: true}
+	pp.update(addlicense.Progress{Found: 2, Processed: 1})
+	pp.Write([]byte("a.go error\n"))
+	pp.update(addlicense.Progress{Found: 2, Processed: 2, WalkDone: true})
+	pp.done()
+	want := "\r\x1b[Kprogress: 1 files processed of 2 found so far, 0s" +
+		"\r\x1b[Ka.go error\nprogress: 1 files processed of 2 found so far, 0s" +
+		"\r\x1b[K[####################] progress: 2/2 files processed (100%), 0s\n"
+	if got := out.String(); got != want {
+		t.Errorf("progress on a terminal is %q, want %q", got, want)
+	}
+
+	// one line per update otherwise
+	out.Reset()
+	pp = &progressPrinter{w: &out}
+	pp.update(addlicense.Progress{Found: 2, Processed: 1})
+	pp.Write([]byte("a.go error\n"))
+	pp.done()
+	if got, want := out.String(), "progress: 1 files processed of 2 found so far, 0s\na.go error\n"; got != want {
+		t.Errorf("progress is %q, want %q", got, want)
+	}
+}
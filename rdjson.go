@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// rdjsonResult is a reviewdog Diagnostic JSON result, see
+// https://github.com/reviewdog/reviewdog/tree/master/proto/rdf.
+type rdjsonResult struct {
+	Source      rdjsonSource       `json:"source"`
+	Diagnostics []rdjsonDiagnostic `json:"diagnostics"`
+}
+
+type rdjsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type rdjsonDiagnostic struct {
+	Message  string         `json:"message"`
+	Location rdjsonLocation `json:"location"`
+	Severity string         `json:"severity"`
+	Code     rdjsonCode     `json:"code"`
+}
+
+type rdjsonLocation struct {
+	Path  string      `json:"path"`
+	Range rdjsonRange `json:"range"`
+}
+
+type rdjsonRange struct {
+	Start rdjsonPosition `json:"start"`
+}
+
+type rdjsonPosition struct {
+	Line int `json:"line"`
+}
+
+type rdjsonCode struct {
+	Value string `json:"value"`
+}
+
+// writeRDJSON writes the findings of report to w as a reviewdog Diagnostic
+// JSON result, with the severity and message of their SARIF rule.
+func writeRDJSON(w io.Writer, report *addlicense.Report) error {
+	res := rdjsonResult{
+		Source:      rdjsonSource{Name: "addlicense", URL: "https://github.com/google/addlicense"},
+		Diagnostics: []rdjsonDiagnostic{},
+	}
+	for _, rule := range sarifRules {
+		for _, path := range report.Paths(rule.status) {
+			res.Diagnostics = append(res.Diagnostics, rdjsonDiagnostic{
+				Message:  rule.message,
+				Location: rdjsonLocation{Path: filepath.ToSlash(filepath.Clean(path)), Range: rdjsonRange{rdjsonPosition{Line: 1}}},
+				Severity: strings.ToUpper(rule.Config.Level),
+				Code:     rdjsonCode{Value: rule.ID},
+			})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
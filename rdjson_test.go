@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestWriteRDJSON(t *testing.T) {
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a/missing.go", Status: addlicense.StatusMissing},
+		{Path: "a/ok.go", Status: addlicense.StatusOK},
+		{Path: "b/dup.py", Status: addlicense.StatusDuplicate},
+	}}
+	var out strings.Builder
+	if err := writeRDJSON(&out, report); err != nil {
+		t.Fatal(err)
+	}
+
+	var res struct {
+		Source      struct{ Name string }
+		Diagnostics []struct {
+			Location struct {
+				Path  string
+				Range struct{ Start struct{ Line int } }
+			}
+			Severity string
+			Code     struct{ Value string }
+		}
+	}
+	if err := json.Unmarshal([]byte(out.String()), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Source.Name != "addlicense" || len(res.Diagnostics) != 2 {
+		t.Fatalf("unexpected rdjson result:\n%s", out.String())
+	}
+	want := []struct{ code, severity, path string }{
+		{"missing-license-header", "ERROR", "a/missing.go"},
+		{"duplicate-license-header", "WARNING", "b/dup.py"},
+	}
+	for i, w := range want {
+		d := res.Diagnostics[i]
+		if d.Code.Value != w.code || d.Severity != w.severity || d.Location.Path != w.path || d.Location.Range.Start.Line != 1 {
+			t.Errorf("diagnostic %d is %+v, want %+v", i, d, w)
+		}
+	}
+}
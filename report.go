@@ -0,0 +1,259 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// maxSummaryGroups is the maximum number of directories listed in the grouped
+// summary of check results.
+const maxSummaryGroups = 20
+
+// writeMissing writes the sorted paths of the files missing a license header
+// to w, one per line. If chunk is positive, the list is split into pages of
+// at most chunk paths, each preceded by a marker line.
+func writeMissing(w io.Writer, paths []string, chunk int) error {
+	for i, path := range paths {
+		if chunk > 0 && i%chunk == 0 {
+			end := i + chunk
+			if end > len(paths) {
+				end = len(paths)
+			}
+			pages := (len(paths) + chunk - 1) / chunk
+			if _, err := fmt.Fprintf(w, "--- page %d/%d: files %d-%d of %d missing license headers ---\n", i/chunk+1, pages, i+1, end, len(paths)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSummary writes to w the number of files missing a license header,
+// grouped by directory, listing the directories with the most files first.
+func writeSummary(w io.Writer, paths []string) error {
+	counts := make(map[string]int)
+	for _, path := range paths {
+		counts[filepath.Dir(path)]++
+	}
+	dirs := make([]string, 0, len(counts))
+	for dir := range counts {
+		dirs = append(dirs, dir)
+	}
+	sort.SliceStable(dirs, func(i, j int) bool {
+		if counts[dirs[i]] != counts[dirs[j]] {
+			return counts[dirs[i]] > counts[dirs[j]]
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	if _, err := fmt.Fprintf(w, "%d files missing license headers in %d directories\n", len(paths), len(dirs)); err != nil {
+		return err
+	}
+	for i, dir := range dirs {
+		if i == maxSummaryGroups {
+			_, err := fmt.Fprintf(w, "  ... and %d more directories\n", len(dirs)-i)
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "  %6d  %s\n", counts[dir], dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportFormats maps the names of the machine readable formats of check
+// results to the functions writing them.
+var reportFormats = map[string]func(w io.Writer, report *addlicense.Report) error{
+	"sarif":       writeSARIF,
+	"rdjson":      writeRDJSON,
+	"codeclimate": writeCodeClimate,
+	"markdown":    writeMarkdown,
+	"json":        writeJSON,
+}
+
+// writeCheckResults writes the check results of report in format to stdout, or
+// to the output file if any. In the default text format, the list of files
+// missing license headers is split into pages of chunk files if positive,
+// colored if color is true when written to stdout, and a summary grouped by
+// directory is printed when writing to the output file.
+func writeCheckResults(report *addlicense.Report, format, output string, chunk int, color bool) error {
+	if write, ok := reportFormats[format]; ok {
+		if output == "" {
+			return write(os.Stdout, report)
+		}
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		if err := write(f, report); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	paths := report.Paths(addlicense.StatusMissing)
+	if output == "" {
+		if color {
+			for i, path := range paths {
+				paths[i] = paint(addlicense.StatusMissing, path)
+			}
+		}
+		return writeMissing(os.Stdout, paths, chunk)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := writeMissing(f, paths, chunk); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := writeSummary(os.Stdout, paths); err != nil {
+		return err
+	}
+	fmt.Printf("full list written to %s\n", output)
+	return nil
+}
+
+// writeProfile writes to w the duration of the stages of the run of report,
+// and statistics of its queue of files waiting for a worker: a queue that is
+// mostly full, with a walk often waiting for room in it, points to slow
+// processing rather than a slow walk.
+func writeProfile(w io.Writer, report *addlicense.Report) error {
+	q := report.Queue
+	_, err := fmt.Fprintf(w, `profile:
+  walk:     %v
+  run:      %v
+  writes:   %v
+  workers:  %d
+  queue:    %d files, capacity %d, max depth %d, mean depth %.1f
+  blocked:  %v waiting for room in the queue
+`,
+		report.WalkEnd.Sub(report.Start).Round(time.Millisecond),
+		report.End.Sub(report.Start).Round(time.Millisecond),
+		report.WriteDuration.Round(time.Millisecond),
+		q.Workers, q.Files, q.Capacity, q.MaxDepth, q.MeanDepth,
+		q.Blocked.Round(time.Millisecond))
+	return err
+}
+
+// writeUnknownExtensions writes to w a histogram of the unknown extensions of
+// the skipped files, the most frequent first, with the comment style likely
+// used by each and the configuration assigning it. Unless verbose is true,
+// nothing is written if no comment style can be suggested, so that the usual
+// data files don't clutter the output of every run.
+func writeUnknownExtensions(w io.Writer, counts map[string]int, verbose bool) error {
+	exts := make([]string, 0, len(counts))
+	suggested := make(map[string]string)
+	total := 0
+	for ext, n := range counts {
+		exts = append(exts, ext)
+		total += n
+		if s := addlicense.SuggestCommentStyle(ext); s != "" {
+			suggested[ext] = s
+		}
+	}
+	if len(exts) == 0 || (len(suggested) == 0 && !verbose) {
+		return nil
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		if counts[exts[i]] != counts[exts[j]] {
+			return counts[exts[i]] > counts[exts[j]]
+		}
+		return exts[i] < exts[j]
+	})
+
+	if _, err := fmt.Fprintf(w, "%d files skipped with unknown extensions:\n", total); err != nil {
+		return err
+	}
+	for _, ext := range exts {
+		line := fmt.Sprintf("  %6d  %s", counts[ext], ext)
+		if s, ok := suggested[ext]; ok {
+			line += fmt.Sprintf("  (likely -ext-style %s=%s)", ext, s)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if len(suggested) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "to process them, add to %s:\n  ext_styles:\n", addlicense.DefaultConfigFile); err != nil {
+		return err
+	}
+	for _, ext := range exts {
+		if s, ok := suggested[ext]; ok {
+			if _, err := fmt.Fprintf(w, "    %s: %s\n", ext, s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeErrorSummary writes to w the number of files of report that failed with
+// an error, by class of error, the most frequent first, and the number of
+// files whose errors were downgraded to warnings. Nothing is written if all
+// files were processed: the errors themselves are logged as they occur, and
+// the summary tells at the end of a long run how many there were.
+func writeErrorSummary(w io.Writer, report *addlicense.Report) error {
+	classes := report.ErrorClasses()
+	failed := 0
+	names := make([]string, 0, len(classes))
+	for class, n := range classes {
+		names = append(names, class)
+		failed += n
+	}
+	warnings := report.Count(addlicense.StatusWarning)
+	if failed == 0 && warnings == 0 {
+		return nil
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if classes[names[i]] != classes[names[j]] {
+			return classes[names[i]] > classes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	if _, err := fmt.Fprintf(w, "%d of %d files failed with errors\n", failed, len(report.Results)); err != nil {
+		return err
+	}
+	for _, class := range names {
+		if _, err := fmt.Fprintf(w, "  %6d  %s\n", classes[class], class); err != nil {
+			return err
+		}
+	}
+	if warnings > 0 {
+		if _, err := fmt.Fprintf(w, "%d files with errors downgraded to warnings\n", warnings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestReportMissing(t *testing.T) {
+	paths := []string{"a/1.go", "b/1.go", "b/2.go"}
+
+	tests := []struct {
+		chunk int
+		want  string
+	}{
+		{0, "a/1.go\nb/1.go\nb/2.go\n"},
+		{2, "--- page 1/2: files 1-2 of 3 missing license headers ---\na/1.go\nb/1.go\n" +
+			"--- page 2/2: files 3-3 of 3 missing license headers ---\nb/2.go\n"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		if err := writeMissing(&out, paths, tt.chunk); err != nil {
+			t.Fatal(err)
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("writeMissing(%d) wrote %q, want %q", tt.chunk, got, tt.want)
+		}
+	}
+
+	var out strings.Builder
+	if err := writeSummary(&out, paths); err != nil {
+		t.Fatal(err)
+	}
+	want := "3 files missing license headers in 2 directories\n       2  b\n       1  a\n"
+	if got := out.String(); got != want {
+		t.Errorf("writeSummary wrote %q, want %q", got, want)
+	}
+}
+
+func TestWriteUnknownExtensions(t *testing.T) {
+	counts := map[string]int{"elm": 3, "json": 5, "nix": 1}
+	var out strings.Builder
+	if err := writeUnknownExtensions(&out, counts, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "9 files skipped with unknown extensions:\n" +
+		"       5  json\n" +
+		"       3  elm  (likely -ext-style elm=dash)\n" +
+		"       1  nix  (likely -ext-style nix=hash)\n" +
+		"to process them, add to .addlicense.yaml:\n" +
+		"  ext_styles:\n" +
+		"    elm: dash\n" +
+		"    nix: hash\n"
+	if got := out.String(); got != want {
+		t.Errorf("writeUnknownExtensions wrote:\n%s\nwant:\n%s", got, want)
+	}
+
+	// data files alone are only listed in verbose mode
+	counts = map[string]int{"json": 5}
+	for _, verbose := range []bool{false, true} {
+		out.Reset()
+		if err := writeUnknownExtensions(&out, counts, verbose); err != nil {
+			t.Fatal(err)
+		}
+		if got := out.String() != ""; got != verbose {
+			t.Errorf("writeUnknownExtensions(verbose %v) wrote %q", verbose, out.String())
+		}
+	}
+}
+
+func TestWriteErrorSummary(t *testing.T) {
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a", Status: addlicense.StatusError, Err: &os.PathError{Op: "open", Path: "a", Err: os.ErrPermission}},
+		{Path: "b", Status: addlicense.StatusError, Err: addlicense.ErrFileTimeout},
+		{Path: "c", Status: addlicense.StatusError, Err: &os.PathError{Op: "open", Path: "c", Err: os.ErrPermission}},
+		{Path: "d", Status: addlicense.StatusWarning},
+		{Path: "e", Status: addlicense.StatusModified},
+	}}
+	var out strings.Builder
+	if err := writeErrorSummary(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	want := "3 of 5 files failed with errors\n" +
+		"       2  permission\n" +
+		"       1  timeout\n" +
+		"1 files with errors downgraded to warnings\n"
+	if got := out.String(); got != want {
+		t.Errorf("writeErrorSummary wrote:\n%s\nwant:\n%s", got, want)
+	}
+
+	// successful runs are quiet
+	out.Reset()
+	report.Results = report.Results[4:]
+	if err := writeErrorSummary(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("writeErrorSummary of a successful run wrote %q", out.String())
+	}
+}
+
+func TestWriteProfile(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := &addlicense.Report{
+		Start:         start,
+		WalkEnd:       start.Add(time.Second),
+		End:           start.Add(3 * time.Second),
+		WriteDuration: 500 * time.Millisecond,
+		Queue: addlicense.QueueStats{
+			Workers:   4,
+			Capacity:  10,
+			Files:     20,
+			MaxDepth:  10,
+			MeanDepth: 7.5,
+			Blocked:   2 * time.Second,
+		},
+	}
+	var out strings.Builder
+	if err := writeProfile(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	want := "profile:\n" +
+		"  walk:     1s\n" +
+		"  run:      3s\n" +
+		"  writes:   500ms\n" +
+		"  workers:  4\n" +
+		"  queue:    20 files, capacity 10, max depth 10, mean depth 7.5\n" +
+		"  blocked:  2s waiting for room in the queue\n"
+	if got := out.String(); got != want {
+		t.Errorf("writeProfile wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+const rollbackHelpText = `Usage: addlicense rollback [flags] snapshot
+
+Restores the files modified by a run started with -snapshot to their contents
+before the run, using the copies saved in the snapshot directory. Files
+modified since the run are left alone unless -force is set, and files already
+restored are skipped, so that a rollback can be repeated safely.
+
+Flags:
+
+`
+
+// rollbackMain implements the rollback subcommand.
+func rollbackMain(args []string) int {
+	fs := flag.NewFlagSet("rollback", flag.ExitOnError)
+	force := fs.Bool("force", false, "also restore the files modified since the run")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, rollbackHelpText)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	results, err := addlicense.Rollback(fs.Arg(0), *force)
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	if writeRollback(os.Stdout, results) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeRollback writes the outcome of a rollback to w and returns the number
+// of files that couldn't be restored.
+func writeRollback(w io.Writer, results []addlicense.RollbackResult) int {
+	failed := 0
+	for _, res := range results {
+		if res.Err != nil {
+			fmt.Fprintf(w, "%s: %v\n", res.Path, res.Err)
+			failed++
+		}
+	}
+	fmt.Fprintf(w, "%d files restored", len(results)-failed)
+	if failed > 0 {
+		fmt.Fprintf(w, ", %d failed", failed)
+	}
+	fmt.Fprintln(w)
+	return failed
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestWriteRollback(t *testing.T) {
+	var b strings.Builder
+	failed := writeRollback(&b, []addlicense.RollbackResult{
+		{Path: "/a.go"},
+		{Path: "/b.go", Err: addlicense.ErrSnapshotConflict},
+	})
+	if failed != 1 {
+		t.Errorf("writeRollback returned %d failures, want 1", failed)
+	}
+	want := "/b.go: modified since the snapshot\n1 files restored, 1 failed\n"
+	if b.String() != want {
+		t.Errorf("writeRollback wrote %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+// sarifRule describes a kind of finding reported in SARIF output.
+type sarifRule struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	ShortDescription sarifMessage      `json:"shortDescription"`
+	FullDescription  sarifMessage      `json:"fullDescription"`
+	Help             sarifMessage      `json:"help"`
+	Config           sarifRuleConfig   `json:"defaultConfiguration"`
+	status           addlicense.Status // status of the files the rule reports
+	message          string            // message of each result
+}
+
+type sarifRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// sarifRules lists the rules of the findings of check only mode.
+var sarifRules = []*sarifRule{
+	{
+		ID:               "missing-license-header",
+		Name:             "MissingLicenseHeader",
+		ShortDescription: sarifMessage{"Source file is missing a license header"},
+		FullDescription:  sarifMessage{"Every source file of a known type must start with a copyright license header, after any hashbang line or similar preamble."},
+		Help:             sarifMessage{"Run addlicense on the file, without -check, to add the license header."},
+		Config:           sarifRuleConfig{"error"},
+		status:           addlicense.StatusMissing,
+		message:          "File is missing a license header. Run addlicense to add it.",
+	},
+	{
+		ID:               "missing-license-footer",
+		Name:             "MissingLicenseFooter",
+		ShortDescription: sarifMessage{"Source file is missing a license footer"},
+		FullDescription:  sarifMessage{"Source files of the configured types must end with a license footer."},
+		Help:             sarifMessage{"Run addlicense on the file with the same -footer flags, without -check, to add the license footer."},
+		Config:           sarifRuleConfig{"error"},
+		status:           addlicense.StatusMissingFooter,
+		message:          "File is missing a license footer. Run addlicense to add it.",
+	},
+	{
+		ID:               "wrong-license-header",
+		Name:             "WrongLicenseHeader",
+		ShortDescription: sarifMessage{"License header doesn't match the assigned license"},
+		FullDescription:  sarifMessage{"The license header of the file is not the one of the license assigned to its subtree by the configuration file."},
+		Help:             sarifMessage{"Run addlicense -replace with the license of the header on the file to replace it with the assigned license."},
+		Config:           sarifRuleConfig{"error"},
+		status:           addlicense.StatusWrongLicense,
+		message:          "File has a license header of another license than the one assigned to it.",
+	},
+	{
+		ID:               "forbidden-license-header",
+		Name:             "ForbiddenLicenseHeader",
+		ShortDescription: sarifMessage{"License header is of a forbidden license"},
+		FullDescription:  sarifMessage{"The license header of the file is of a license forbidden in the project, such as a copyleft license in a permissively licensed project."},
+		Help:             sarifMessage{"Remove the file, or obtain it under a license compatible with the project."},
+		Config:           sarifRuleConfig{"error"},
+		status:           addlicense.StatusForbidden,
+		message:          "File has a license header of a forbidden license.",
+	},
+	{
+		ID:               "duplicate-license-header",
+		Name:             "DuplicateLicenseHeader",
+		ShortDescription: sarifMessage{"License header is stacked twice"},
+		FullDescription:  sarifMessage{"The license header of the file is followed by a second license header, usually left behind by past tooling mishaps."},
+		Help:             sarifMessage{"Run addlicense -fix-duplicates on the file to remove the redundant copy."},
+		Config:           sarifRuleConfig{"warning"},
+		status:           addlicense.StatusDuplicate,
+		message:          "File has a duplicate license header. Run addlicense -fix-duplicates to remove it.",
+	},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// writeSARIF writes the findings of report to w as a SARIF 2.1.0 log, which
+// GitHub code scanning shows as alerts.
+func writeSARIF(w io.Writer, report *addlicense.Report) error {
+	run := sarifRun{
+		Tool: sarifTool{sarifDriver{
+			Name:           "addlicense",
+			InformationURI: "https://github.com/google/addlicense",
+			Rules:          sarifRules,
+		}},
+		Results: []sarifResult{},
+	}
+	for i, rule := range sarifRules {
+		for _, path := range report.Paths(rule.status) {
+			loc := sarifArtifactLocation{URI: filepath.ToSlash(filepath.Clean(path))}
+			if !filepath.IsAbs(path) {
+				loc.URIBaseID = "%SRCROOT%"
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    rule.ID,
+				RuleIndex: i,
+				Level:     rule.Config.Level,
+				Message:   sarifMessage{rule.message},
+				Locations: []sarifLocation{{sarifPhysicalLocation{loc, sarifRegion{1}}}},
+			})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
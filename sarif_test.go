@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	report := &addlicense.Report{Results: []addlicense.Result{
+		{Path: "a/missing.go", Status: addlicense.StatusMissing},
+		{Path: "a/ok.go", Status: addlicense.StatusOK},
+		{Path: "b/dup.py", Status: addlicense.StatusDuplicate},
+	}}
+	var out strings.Builder
+	if err := writeSARIF(&out, report); err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string
+					Rules []struct{ ID string }
+				}
+			}
+			Results []struct {
+				RuleID    string
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI, URIBaseID string }
+						Region           struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(out.String()), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Name != "addlicense" {
+		t.Fatalf("unexpected SARIF log:\n%s", out.String())
+	}
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2:\n%s", len(results), out.String())
+	}
+	want := []struct{ rule, level, uri string }{
+		{"missing-license-header", "error", "a/missing.go"},
+		{"duplicate-license-header", "warning", "b/dup.py"},
+	}
+	for i, w := range want {
+		r := results[i]
+		loc := r.Locations[0].PhysicalLocation
+		if r.RuleID != w.rule || r.Level != w.level || loc.ArtifactLocation.URI != w.uri || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" || loc.Region.StartLine != 1 {
+			t.Errorf("result %d is %+v, want %+v", i, r, w)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"text/template"
+
+	"github.com/google/addlicense/pkg/addlicense"
+)
+
+const testTemplateHelpText = `Usage: addlicense test-template -f template -golden dir [flags]
+
+Renders a license template in every comment style and compares the results
+with the golden files found in dir, one per style named after it, such as
+"hash.golden" or "c.golden". Differences are printed as unified diffs, so
+that template changes can be reviewed as such.
+
+The exit code is 1 if any rendered header differs from its golden file.
+
+Flags:
+
+`
+
+// testTemplateMain implements the test-template subcommand.
+func testTemplateMain(args []string) int {
+	fs := flag.NewFlagSet("test-template", flag.ExitOnError)
+	tplFile := fs.String("f", "", "license template file")
+	golden := fs.String("golden", "", "directory of golden files")
+	update := fs.Bool("update", false, "write the rendered headers to the golden files instead of comparing them")
+	data := addlicense.LicenseData{}
+	fs.StringVar(&data.Holder, "c", "HOLDER", "copyright holder to render the template with")
+	fs.StringVar(&data.Year, "y", "YEAR", "copyright year(s) to render the template with")
+	fs.StringVar(&data.SPDXID, "l", "Apache-2.0", "SPDX identifier to render the template with")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, testTemplateHelpText)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *tplFile == "" || *golden == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	d, err := ioutil.ReadFile(*tplFile)
+	if err != nil {
+		log.Printf("license file: %v", err)
+		return 2
+	}
+	tmpl, err := template.New("").Parse(string(d))
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	if *update {
+		err = addlicense.UpdateGoldens(*golden, tmpl, data)
+		if err != nil {
+			log.Print(err)
+			return 2
+		}
+		return 0
+	}
+	n, err := addlicense.CompareGoldens(os.Stdout, *golden, tmpl, data)
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	if n > 0 {
+		fmt.Fprintf(os.Stdout, "%d comment styles differ from their golden files\n", n)
+		return 1
+	}
+	return 0
+}